@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/shillgptbot"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+)
+
+var (
+	permissionsChatID  *int64
+	permissionsCommand *string
+	permissionsPolicy  *string
+	permissionsUsers   *string
+	permissionsRole    *string
+	permissionsReset   *bool
+)
+
+// permissionsCmd represents the permissions command
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Manage who may run each bot command in a chat",
+	Long: `Each command has a policy: everyone, admins, allowlist (the listed user IDs) or
+role (members tagged with the role). Chat admins and bot managers can always run
+every command. Chats without a policy use permissions.defaults.<command>, /config
+and /usage default to admins and every other command to everyone.`,
+}
+
+// permissionsShowCmd represents the permissions show command
+var permissionsShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Print the chat's command policies and roles",
+	PreRunE: permissionsCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := permissionsConfig()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "COMMAND\tPOLICY\tUSERS\tROLE")
+		for _, command := range shillgptbot.Commands() {
+			policy, ok := c.Permissions.Commands[command]
+			if !ok {
+				fmt.Fprintf(w, "%s\t(default)\t\t\n", command)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", command, policy.Policy, formatUserIDs(policy.Users), policy.Role)
+		}
+
+		roles := make([]string, 0, len(c.Permissions.Roles))
+		for role := range c.Permissions.Roles {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+
+		fmt.Fprintln(w, "\nROLE\tUSERS")
+		for _, role := range roles {
+			fmt.Fprintf(w, "%s\t%s\n", role, formatUserIDs(c.Permissions.Roles[role]))
+		}
+
+		fmt.Fprintf(w, "\nMANAGERS\t%s\n", formatUserIDs(c.Managers))
+
+		return w.Flush()
+	},
+}
+
+// permissionsSetCmd represents the permissions set command
+var permissionsSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Set the policy for a command, or --reset to the default",
+	PreRunE: permissionsCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !contains(shillgptbot.Commands(), *permissionsCommand) {
+			return fmt.Errorf("--command must be one of %s", strings.Join(shillgptbot.Commands(), ", "))
+		}
+
+		c, err := permissionsConfig()
+		if err != nil {
+			return err
+		}
+
+		if *permissionsReset {
+			delete(c.Permissions.Commands, *permissionsCommand)
+			return c.Update(&c)
+		}
+
+		policy := config.CommandPolicy{
+			Policy: *permissionsPolicy,
+			Role:   *permissionsRole,
+		}
+
+		if *permissionsUsers != "" {
+			if policy.Users, err = config.ParseUserIDs(*permissionsUsers); err != nil {
+				return err
+			}
+		}
+
+		if err := config.ValidateCommandPolicy(policy); err != nil {
+			return err
+		}
+
+		c.SetCommandPolicy(*permissionsCommand, policy)
+		return c.Update(&c)
+	},
+}
+
+// permissionsRoleCmd represents the permissions role command
+var permissionsRoleCmd = &cobra.Command{
+	Use:     "role",
+	Short:   "Tag members with a role, or --reset to remove the role",
+	PreRunE: permissionsCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if *permissionsRole == "" {
+			return fmt.Errorf("--role is required")
+		}
+
+		c, err := permissionsConfig()
+		if err != nil {
+			return err
+		}
+
+		var userIDs []int64
+		if !*permissionsReset {
+			if userIDs, err = config.ParseUserIDs(*permissionsUsers); err != nil {
+				return err
+			}
+		}
+
+		c.SetRole(*permissionsRole, userIDs)
+		return c.Update(&c)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(permissionsCmd)
+	permissionsCmd.AddCommand(permissionsShowCmd)
+	permissionsCmd.AddCommand(permissionsSetCmd)
+	permissionsCmd.AddCommand(permissionsRoleCmd)
+
+	permissionsChatID = permissionsCmd.PersistentFlags().Int64("chat", 0, "Telegram chat ID")
+	permissionsUsers = permissionsCmd.PersistentFlags().String("users", "", "Comma separated Telegram user IDs")
+	permissionsRole = permissionsCmd.PersistentFlags().String("role", "", "Role tag e.g. mods")
+	permissionsReset = permissionsCmd.PersistentFlags().Bool("reset", false, "Remove the policy or role")
+	permissionsCommand = permissionsSetCmd.Flags().String("command", "", fmt.Sprintf("Command, one of %s", strings.Join(shillgptbot.Commands(), ", ")))
+	permissionsPolicy = permissionsSetCmd.Flags().String("policy", config.POLICY_EVERYONE, fmt.Sprintf("Policy, one of %s", strings.Join(config.Policies(), ", ")))
+}
+
+// permissionsCmdValidate
+func permissionsCmdValidate(cmd *cobra.Command, args []string) error {
+	if *permissionsChatID == 0 {
+		return fmt.Errorf("--chat is required")
+	}
+
+	return nil
+}
+
+// permissionsConfig
+func permissionsConfig() (config.Config, error) {
+	return chatConfig(*permissionsChatID)
+}
+
+// chatConfig - the chat must have run /config at least once
+func chatConfig(chatID int64) (config.Config, error) {
+	c, found, err := config.ConfigByChatID(storage.NewMongo(), chatID)
+	if err != nil {
+		return c, err
+	}
+
+	if !found {
+		return c, fmt.Errorf("no config found for chat %d", chatID)
+	}
+
+	return c, nil
+}
+
+// formatUserIDs
+func formatUserIDs(userIDs []int64) string {
+	ids := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = fmt.Sprint(userID)
+	}
+
+	return strings.Join(ids, ",")
+}
+
+// contains
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
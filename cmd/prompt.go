@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/language"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/persona"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/prompt"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+)
+
+var (
+	promptChatID    *int64
+	promptReplyType *string
+	promptTweetText *string
+	promptFile      *string
+	promptReset     *bool
+)
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Manage the prompt templates used to generate replies",
+	Long: `Prompt templates use Go text/template syntax. Every chat config field is available
+e.g. {{.Token}}, {{.Community}}, {{.Hashtags}} and {{.Cashtags}}, along with
+{{.ReplyType}}, {{.TweetText}}, {{.CharLimit}}, {{.Style}} and {{.Language}}.
+The shared partials {{template "tags" .}}, {{template "rules" .}}, {{template "language" .}}
+and {{template "respond" .}} can be used by any template.`,
+}
+
+// promptRenderCmd represents the prompt render command
+var promptRenderCmd = &cobra.Command{
+	Use:     "render",
+	Short:   "Render the prompt a chat would send for a reply type",
+	PreRunE: promptCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mongo := storage.NewMongo()
+
+		c, found, err := config.ConfigByChatID(mongo, *promptChatID)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return fmt.Errorf("no config found for chat %d", *promptChatID)
+		}
+
+		data := prompt.SampleData(*promptReplyType)
+		data.Config = c
+		if *promptTweetText != "" {
+			data.TweetText = *promptTweetText
+		}
+
+		if l, ok := language.ForTweet(data.TweetText, c.Language.Preferred, c.Language.Forced); ok {
+			data.Language = l.Name
+		}
+
+		instruction, err := prompt.Render(mongo, data)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(instruction)
+		return nil
+	},
+}
+
+// promptSetCmd represents the prompt set command
+var promptSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Override a chat's prompt template, or --reset to the built-in default",
+	PreRunE: promptCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mongo := storage.NewMongo()
+
+		pt, found, err := prompt.PromptTemplateByChatID(mongo, *promptChatID, *promptReplyType)
+		if err != nil {
+			return err
+		}
+
+		if *promptReset {
+			if !found {
+				return nil
+			}
+			return pt.Delete(&pt)
+		}
+
+		if *promptFile == "" {
+			return fmt.Errorf("--file is required unless --reset is given")
+		}
+
+		text, err := os.ReadFile(*promptFile)
+		if err != nil {
+			return err
+		}
+
+		if err := prompt.Validate(string(text), *promptReplyType); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+
+		pt.ChatID = *promptChatID
+		pt.ReplyType = *promptReplyType
+		pt.Template = string(text)
+
+		if found {
+			return pt.Update(&pt)
+		}
+
+		return pt.Insert(&pt)
+	},
+}
+
+// promptDefaultCmd represents the prompt default command
+var promptDefaultCmd = &cobra.Command{
+	Use:   "default",
+	Short: "Print the built-in template for a reply type, a starting point for overrides",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		text, ok := prompt.DefaultTemplate(*promptReplyType)
+		if !ok {
+			return fmt.Errorf("no built-in template for reply type %q", *promptReplyType)
+		}
+
+		fmt.Println(text)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.AddCommand(promptRenderCmd)
+	promptCmd.AddCommand(promptSetCmd)
+	promptCmd.AddCommand(promptDefaultCmd)
+
+	promptChatID = promptCmd.PersistentFlags().Int64("chat", 0, "Telegram chat ID")
+	promptReplyType = promptCmd.PersistentFlags().String("type", persona.PERSONA_SHILL, fmt.Sprintf("Reply type, one of %s", strings.Join(persona.Names(), ", ")))
+	promptTweetText = promptRenderCmd.Flags().String("tweet", "", "Tweet text to render with, a sample is used by default")
+	promptFile = promptSetCmd.Flags().String("file", "", "File containing the template")
+	promptReset = promptSetCmd.Flags().Bool("reset", false, "Remove the chat's override")
+}
+
+// promptCmdValidate
+func promptCmdValidate(cmd *cobra.Command, args []string) error {
+	if *promptChatID == 0 {
+		return fmt.Errorf("--chat is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/quota"
+)
+
+var (
+	quotaChatID  *int64
+	quotaDaily   *int
+	quotaMonthly *int
+	quotaReset   *bool
+)
+
+// quotaCmd represents the quota command
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Manage the generations a chat is allowed",
+	Long: `Chats without their own limits use quota.daily and quota.monthly. A limit of 0
+uses the default and a negative limit is unlimited.`,
+}
+
+// quotaShowCmd represents the quota show command
+var quotaShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Print the chat's own limits and the limits in effect",
+	PreRunE: quotaCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := chatConfig(*quotaChatID)
+		if err != nil {
+			return err
+		}
+
+		limits := quota.LimitsForChat(c)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PERIOD\tCHAT\tIN EFFECT")
+		fmt.Fprintf(w, "daily\t%s\t%s\n", formatQuotaSetting(c.Quota.Daily), formatQuotaLimit(limits.Daily))
+		fmt.Fprintf(w, "monthly\t%s\t%s\n", formatQuotaSetting(c.Quota.Monthly), formatQuotaLimit(limits.Monthly))
+
+		return w.Flush()
+	},
+}
+
+// quotaSetCmd represents the quota set command
+var quotaSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Set the chat's daily and/or monthly limit, or --reset to the defaults",
+	PreRunE: quotaCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		if !*quotaReset && !flags.Changed("daily") && !flags.Changed("monthly") {
+			return fmt.Errorf("--daily, --monthly or --reset is required")
+		}
+
+		c, err := chatConfig(*quotaChatID)
+		if err != nil {
+			return err
+		}
+
+		if *quotaReset {
+			c.Quota.Daily = 0
+			c.Quota.Monthly = 0
+			return c.Update(&c)
+		}
+
+		if flags.Changed("daily") {
+			c.Quota.Daily = *quotaDaily
+		}
+
+		if flags.Changed("monthly") {
+			c.Quota.Monthly = *quotaMonthly
+		}
+
+		return c.Update(&c)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(quotaCmd)
+	quotaCmd.AddCommand(quotaShowCmd)
+	quotaCmd.AddCommand(quotaSetCmd)
+
+	quotaChatID = quotaCmd.PersistentFlags().Int64("chat", 0, "Telegram chat ID")
+	quotaDaily = quotaSetCmd.Flags().Int("daily", 0, "Generations per day, 0 for the default and negative for unlimited")
+	quotaMonthly = quotaSetCmd.Flags().Int("monthly", 0, "Generations per month, 0 for the default and negative for unlimited")
+	quotaReset = quotaSetCmd.Flags().Bool("reset", false, "Use the default limits")
+}
+
+// quotaCmdValidate
+func quotaCmdValidate(cmd *cobra.Command, args []string) error {
+	if *quotaChatID == 0 {
+		return fmt.Errorf("--chat is required")
+	}
+
+	return nil
+}
+
+// formatQuotaSetting
+func formatQuotaSetting(setting int) string {
+	if setting == 0 {
+		return "(default)"
+	}
+
+	return formatQuotaLimit(setting)
+}
+
+// formatQuotaLimit
+func formatQuotaLimit(limit int) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprint(limit)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+)
+
+var (
+	stateChatID *int64
+	stateTTL    *time.Duration
+	stateReset  *bool
+)
+
+// stateCmd represents the state command
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage how long a chat's in-flight commands are kept",
+	Long: `State is kept for state.ttl after a command was last used, chats can have their
+own ttl instead. Running bots pick up a new ttl the next time the state is saved.`,
+}
+
+// stateShowCmd represents the state show command
+var stateShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Print the chat's state ttl",
+	PreRunE: stateCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := chatConfig(*stateChatID)
+		if err != nil {
+			return err
+		}
+
+		if c.StateTTL <= 0 {
+			fmt.Printf("%s (default)\n", storage.DefaultStateTTL())
+			return nil
+		}
+
+		fmt.Println(c.StateTTL)
+		return nil
+	},
+}
+
+// stateSetCmd represents the state set command
+var stateSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Set the chat's state ttl, or --reset to the default",
+	PreRunE: stateCmdValidate,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !*stateReset {
+			if !cmd.Flags().Changed("ttl") {
+				return fmt.Errorf("--ttl or --reset is required")
+			}
+
+			if err := config.ValidateStateTTL(*stateTTL); err != nil {
+				return err
+			}
+		}
+
+		c, err := chatConfig(*stateChatID)
+		if err != nil {
+			return err
+		}
+
+		c.StateTTL = 0
+		if !*stateReset {
+			c.StateTTL = *stateTTL
+		}
+
+		return c.Update(&c)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateShowCmd)
+	stateCmd.AddCommand(stateSetCmd)
+
+	stateChatID = stateCmd.PersistentFlags().Int64("chat", 0, "Telegram chat ID")
+	stateTTL = stateSetCmd.Flags().Duration("ttl", 0, "How long state is kept after the chat last used it e.g. 2h")
+	stateReset = stateSetCmd.Flags().Bool("reset", false, "Use the default state.ttl")
+}
+
+// stateCmdValidate
+func stateCmdValidate(cmd *cobra.Command, args []string) error {
+	if *stateChatID == 0 {
+		return fmt.Errorf("--chat is required")
+	}
+
+	return nil
+}
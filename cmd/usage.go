@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/usage"
+)
+
+var (
+	usageChatID *int64
+	usageDays   *int
+)
+
+// usageCmd represents the usage command
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Token usage and estimated cost of generated replies",
+}
+
+// usageReportCmd represents the usage report command
+var usageReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report usage per chat and model, most expensive first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mongo := storage.NewMongo()
+
+		var since time.Time
+		if *usageDays > 0 {
+			since = time.Now().AddDate(0, 0, -*usageDays)
+		}
+
+		var (
+			totals []usage.Totals
+			err    error
+		)
+		if *usageChatID != 0 {
+			totals, err = usage.TotalsByChatID(mongo, *usageChatID, since)
+		} else {
+			totals, err = usage.AllTotals(mongo, since)
+		}
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHAT\tMODEL\tREQUESTS\tPROMPT TOKENS\tCOMPLETION TOKENS\tCOST (USD)")
+		for _, t := range totals {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%.4f\n", t.ChatID, t.Model, t.Requests, t.PromptTokens, t.CompletionTokens, t.Cost)
+		}
+
+		sum := usage.Sum(totals)
+		fmt.Fprintf(w, "TOTAL\t\t%d\t%d\t%d\t%.4f\n", sum.Requests, sum.PromptTokens, sum.CompletionTokens, sum.Cost)
+
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+	usageCmd.AddCommand(usageReportCmd)
+
+	usageChatID = usageReportCmd.Flags().Int64("chat", 0, "Only report this Telegram chat ID")
+	usageDays = usageReportCmd.Flags().Int("days", 30, "Report the last n days, 0 for all time")
+}
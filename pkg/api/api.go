@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 
 	"github.com/labstack/echo/v4"
 	em "github.com/labstack/echo/v4/middleware"
 	gl "github.com/labstack/gommon/log"
+	"github.com/spf13/viper"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
 
 	"go.uber.org/zap"
@@ -60,6 +62,10 @@ func (a *Api) Serve() {
 	v := NewValidator()
 	e.Validator = v
 
+	// visitor IPs are used for rate limits so they can't come from headers the
+	// client controls, see ipExtractor
+	e.IPExtractor = a.ipExtractor()
+
 	e.Use(em.Logger()) // logger em will “wrap” recovery
 	// e.Use(em.RequestLoggerWithConfig(em.RequestLoggerConfig{
 	// 	LogURI:    true,
@@ -113,6 +119,35 @@ func (a *Api) BasePath() string {
 	return basePath
 }
 
+// ipExtractor - the connection's address, unless api.trustedProxies lists the
+// proxies (CIDRs) in front of the api whose X-Forwarded-For is trusted
+func (a *Api) ipExtractor() echo.IPExtractor {
+	proxies := viper.GetStringSlice("api.trustedProxies")
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range proxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			a.logger.Fatal(
+				"invalid trusted proxy",
+				zap.String("proxy", proxy),
+				zap.Error(err),
+			)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
 func (a *Api) healthCheck(c echo.Context) error {
 	return ReturnSuccessMessage(c, "We're alive!")
 }
@@ -6,11 +6,11 @@ var (
 	ErrUnknownError     = errors.New("an unknown error occurred")
 	ErrRequestBindError = errors.New("unable to bind request object")
 
-	ErrOpenAiReplyLength = errors.New("we had a problem generating a reply with the correct character count, please try again")
-
 	ErrMockFatalError    = errors.New("a fatal error occurred")
 	ErrMockNotFound      = errors.New("not found")
 	ErrMockNotAuthorised = errors.New("not authorised")
 
-	ErrShillNotFound = errors.New("could not find that shill request")
+	ErrShillNotFound   = errors.New("could not find that shill request")
+	ErrVariantNotFound = errors.New("could not find that reply variant")
+	ErrConfigNotFound  = errors.New("this chat has not been configured")
 )
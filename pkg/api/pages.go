@@ -0,0 +1,68 @@
+package api
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+var messagePage = template.Must(template.New("message").Parse(messagePageHTML))
+
+type messagePageData struct {
+	Title   string
+	Message string
+}
+
+// ReturnMessagePage - a friendly HTML page for people following shill links in a browser
+func ReturnMessagePage(c echo.Context, code int, title string, message string) error {
+	var page bytes.Buffer
+	if err := messagePage.Execute(&page, messagePageData{Title: title, Message: message}); err != nil {
+		return c.String(code, message)
+	}
+
+	return c.HTML(code, page.String())
+}
+
+// ReturnTooManyRequests - returns a 429 page
+func ReturnTooManyRequests(c echo.Context, title string, message string) error {
+	return ReturnMessagePage(c, http.StatusTooManyRequests, title, message)
+}
+
+// ReturnQuotaReached - returns a 429 page when a chat has used its generation quota
+func ReturnQuotaReached(c echo.Context) error {
+	return ReturnTooManyRequests(c, "Quota reached", "This community has used all of its AI replies for now, please try again later.")
+}
+
+// ReturnRateLimited - returns a 429 page when requests are coming too fast
+func ReturnRateLimited(c echo.Context) error {
+	return ReturnTooManyRequests(c, "Slow down!", "Too many replies requested, please wait a moment and try again.")
+}
+
+// ReturnReplyRejected - returns a 422 page when every generated reply failed moderation
+func ReturnReplyRejected(c echo.Context) error {
+	return ReturnMessagePage(c, http.StatusUnprocessableEntity, "No reply this time", "We couldn't come up with a reply we're happy for you to post, please try again.")
+}
+
+const messagePageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; background: #000; color: #e7e9ea; margin: 0; padding: 16px; }
+main { max-width: 600px; margin: 15vh auto 0; text-align: center; }
+h1 { font-size: 1.6em; }
+p { color: #71767b; font-size: 1.1em; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+</main>
+</body>
+</html>
+`
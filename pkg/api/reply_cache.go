@@ -0,0 +1,125 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/shillx"
+	"go.uber.org/zap"
+)
+
+const (
+	visitorCookieName   = "shill_visitor"
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
+)
+
+// cachedReply - the reply generated for a visitor of a shill link, only ever
+// returned to that visitor so replies are still unique across visitors
+type cachedReply struct {
+	ShillID string `json:"shillId"`
+	Reply   string `json:"reply"`
+}
+
+// replyCacheWindow - how long a visitor gets the same reply back, configured by
+// replyCache.window, 0 disables the cache
+func replyCacheWindow() time.Duration {
+	return viper.GetDuration("replyCache.window")
+}
+
+// visitorID - the visitor fingerprint from the visitor cookie, set when missing
+func visitorID(c echo.Context) string {
+	if cookie, err := c.Cookie(visitorCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	id := hex.EncodeToString(b)
+
+	c.SetCookie(&http.Cookie{
+		Name:     visitorCookieName,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   visitorCookieMaxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return id
+}
+
+// replyCacheKey
+func replyCacheKey(sl *shillx.ShillLink, visitor string) string {
+	return fmt.Sprintf("replycache:%s:%s", sl.ID.Hex(), visitor)
+}
+
+// cachedReply - the visitor's previous reply for the shill link within the cache window
+func (ss *shillService) cachedReply(sl *shillx.ShillLink, visitor string) (cachedReply, bool) {
+	var cr cachedReply
+	if replyCacheWindow() <= 0 || visitor == "" {
+		return cr, false
+	}
+
+	ok, err := ss.cache.Load(replyCacheKey(sl, visitor), &cr)
+	if err != nil {
+		ss.a.logger.Warn(
+			"unable to load cached reply",
+			zap.String("shillID", sl.ID.Hex()),
+			zap.Error(err),
+		)
+		return cr, false
+	}
+
+	return cr, ok
+}
+
+// cacheReply
+func (ss *shillService) cacheReply(sl *shillx.ShillLink, visitor string, s *shillx.Shill) {
+	if replyCacheWindow() <= 0 || visitor == "" {
+		return
+	}
+
+	cr := cachedReply{
+		ShillID: s.ID.Hex(),
+		Reply:   s.Reply,
+	}
+
+	if err := ss.cache.Save(replyCacheKey(sl, visitor), cr, replyCacheWindow()); err != nil {
+		ss.a.logger.Warn(
+			"unable to cache reply",
+			zap.String("shillID", sl.ID.Hex()),
+			zap.Error(err),
+		)
+	}
+}
+
+// replyFromCache - answer the request from the visitor's cached reply, false when
+// there isn't one and a reply needs generating
+func (ss *shillService) replyFromCache(c echo.Context, sl *shillx.ShillLink, visitor string) (bool, error) {
+	cr, ok := ss.cachedReply(sl, visitor)
+	if !ok {
+		return false, nil
+	}
+
+	if variantCount() <= 1 {
+		if cr.Reply == "" {
+			return false, nil
+		}
+
+		return true, c.Redirect(http.StatusFound, tweetIntentURL(sl.TweetID, cr.Reply))
+	}
+
+	s, found, err := shillx.ShillByID(ss.a.mongo, cr.ShillID)
+	if err != nil || !found || len(s.Variants) == 0 {
+		return false, nil
+	}
+
+	return true, ss.renderVariantsPage(c, sl, s)
+}
@@ -2,37 +2,91 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/labstack/echo/v4"
-	openai "github.com/sashabaranov/go-openai"
+	"github.com/spf13/viper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/brandsafety"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/shillx"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/language"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/llm"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/moderation"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/prompt"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/quota"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/similarity"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/twittertext"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/usage"
 	"go.uber.org/zap"
 )
 
-const shillServiceBasePath string = "/shill"
+const (
+	shillServiceBasePath string = "/shill"
+
+	defaultDuplicateThreshold = 0.5
+	defaultDuplicateLookback  = 50
+	maxDifferentExamples      = 5
+)
 
 // shillService
 type shillService struct {
-	a *Api
+	a         *Api
+	generator llm.ReplyGenerator
+	moderator *moderation.Moderator
+	quota     *quota.Quota
+	limiter   *quota.RateLimiter
+	cache     storage.StateStore
 }
 
 // newShillService
 func newShillService(a *Api) *shillService {
+	generator, err := llm.NewReplyGenerator()
+	if err != nil {
+		a.logger.Fatal(
+			"unable to create reply generator",
+			zap.String("provider", viper.GetString("llm.provider")),
+			zap.Error(err),
+		)
+	}
+
+	moderator, err := moderation.NewModerator()
+	if err != nil {
+		a.logger.Fatal(
+			"unable to create moderator",
+			zap.String("provider", viper.GetString("moderation.provider")),
+			zap.Error(err),
+		)
+	}
+
+	counter := storage.SharedCounter()
+
 	return &shillService{
-		a: a,
+		a:         a,
+		generator: generator,
+		moderator: moderator,
+		quota:     quota.NewQuota(counter),
+		limiter:   quota.NewRateLimiter(counter),
+		cache:     storage.SharedStateStore(),
 	}
 }
 
+// SetGenerator - replace the reply generator e.g. with llm.FakeGenerator in tests
+func (ss *shillService) SetGenerator(generator llm.ReplyGenerator) {
+	ss.generator = generator
+}
+
 // LoadRoutes
 func (ss *shillService) LoadRoutes(parentGroup *echo.Group) {
-	g := parentGroup.Group(shillServiceBasePath)
+	g := parentGroup.Group(shillServiceBasePath, ss.rateLimit)
 
 	g.GET("/:shillID", ss.createTwitterReply)
+	g.POST("/:shillID/regenerate", ss.regenerateVariants)
+	g.POST("/:shillID/pick/:replyID/:index", ss.pickVariant)
 }
 
 // createTwitterReply
@@ -58,13 +112,32 @@ func (ss *shillService) createTwitterReply(c echo.Context) error {
 		return ReturnError(c, ErrShillNotFound)
 	}
 
-	reply, err := ss.generateReply(sl)
+	// reloads and double clicks get the visitor's previous reply without generating
+	visitor := visitorID(c)
+	if handled, err := ss.replyFromCache(c, sl, visitor); handled {
+		return err
+	}
+
+	if variantCount() > 1 {
+		return ss.renderVariants(c, sl, visitor)
+	}
+
+	reply, err := ss.generateReply(c.Request().Context(), sl, "")
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		return ReturnQuotaReached(c)
+	}
+
+	if errors.Is(err, moderation.ErrRejected) {
+		return ReturnReplyRejected(c)
+	}
+
 	if err != nil {
 		return ReturnError(c, err)
 	}
 
 	// store the reply
 	s := shillx.NewShill(ss.a.mongo)
+	s.ShillLinkID = sl.ID
 	s.ChatID = sl.ChatID
 	s.TweetID = sl.TweetID
 	s.Reply = reply
@@ -74,69 +147,222 @@ func (ss *shillService) createTwitterReply(c echo.Context) error {
 			zap.String("shillID", shillID),
 		)
 	}
+	ss.cacheReply(sl, visitor, s)
+
+	return c.Redirect(http.StatusFound, tweetIntentURL(sl.TweetID, reply))
+}
+
+// rateLimit - limit requests per visitor IP and per shill link
+func (ss *shillService) rateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		limits := []struct {
+			name string
+			id   string
+		}{
+			{"ip", c.RealIP()},
+			{"shillLink", c.Param("shillID")},
+		}
+
+		for _, l := range limits {
+			allowed, err := ss.limiter.Allow(ctx, l.name, l.id, quota.RateLimitFromConfig(l.name))
+			if err != nil {
+				ss.a.logger.Error(
+					"an error occurred checking the rate limit, allowing request",
+					zap.String("limit", l.name),
+					zap.Error(err),
+				)
+				continue
+			}
+
+			if !allowed {
+				return ReturnRateLimited(c)
+			}
+		}
+
+		return next(c)
+	}
+}
+
+// consumeQuota - count one generation against the chat's quota, false when the
+// quota is used up. Quota errors are logged and the generation allowed.
+func (ss *shillService) consumeQuota(ctx context.Context, c config.Config, sl *shillx.ShillLink) bool {
+	err := ss.quota.Consume(ctx, sl.ChatID, quota.LimitsForChat(c))
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		ss.a.logger.Info(
+			"generation quota reached",
+			zap.Int64("chatID", sl.ChatID),
+			zap.String("shillID", sl.ID.Hex()),
+		)
+		return false
+	}
+
+	if err != nil {
+		ss.a.logger.Error(
+			"an error occurred checking the generation quota, allowing generation",
+			zap.Int64("chatID", sl.ChatID),
+			zap.Error(err),
+		)
+	}
 
-	redirectUrl := fmt.Sprintf("https://twitter.com/intent/tweet?in_reply_to=%s&text=%s", sl.TweetID, url.QueryEscape(reply))
+	return true
+}
 
-	return c.Redirect(http.StatusFound, redirectUrl)
+// tweetIntentURL - the X compose url for a reply to tweetID
+func tweetIntentURL(tweetID string, reply string) string {
+	return fmt.Sprintf("https://twitter.com/intent/tweet?in_reply_to=%s&text=%s", tweetID, url.QueryEscape(reply))
 }
 
-// generateReply
-func (ss *shillService) generateReply(sl *shillx.ShillLink) (string, error) {
+// generateReply - style optionally steers the tone of the reply. Every attempt is
+// charged to the chat's quota, quota.ErrQuotaExceeded once it's used up
+func (ss *shillService) generateReply(ctx context.Context, sl *shillx.ShillLink, style string) (string, error) {
 
-	maxChars := 280
+	maxChars := twittertext.MaxWeightedLength
 	charLimit := 260
 
-	instruction := fmt.Sprintf(
-		"%s. Respond to the following tweet in your unique style and keep the response to a maximum of %d characters: '%v'",
-		ss.aiInstruction(sl),
-		charLimit,
-		sl.TweetText,
-	)
+	c, err := ss.chatConfig(sl)
+	if err != nil {
+		return "", err
+	}
+
+	baseInstruction, err := ss.aiInstruction(c, sl, charLimit, style)
+	if err != nil {
+		return "", err
+	}
+	instruction := baseInstruction
 
-	client := openaiClient()
+	earlier := ss.earlierReplies(sl)
 
 	attempt := 1
 	maxAttempts := 3
 	reply := ""
-	var resp openai.ChatCompletionResponse
-	var err error
+	rejected := false
 	for {
 		if attempt > maxAttempts {
-			return "", ErrOpenAiReplyLength
+			if rejected {
+				ss.a.logger.Warn(
+					"reply still failing moderation after max attempts, rejecting",
+					zap.String("shillID", sl.ID.Hex()),
+				)
+				return "", moderation.ErrRejected
+			}
+
+			ss.a.logger.Warn(
+				"reply still too long after max attempts, truncating",
+				zap.String("shillID", sl.ID.Hex()),
+				zap.Int("length", twittertext.WeightedLength(reply)),
+			)
+			reply = twittertext.Truncate(reply, maxChars)
+			if !ss.moderate(ctx, c, sl, attempt-1, reply) {
+				return "", moderation.ErrRejected
+			}
+
+			return brandsafety.ApplySuffix(c.BrandSafety, reply, maxChars), nil
 		}
 
-		resp, err = client.CreateChatCompletion(
-			context.Background(),
-			openai.ChatCompletionRequest{
-				Model: openai.GPT4TurboPreview,
-				Messages: []openai.ChatCompletionMessage{
-					{
-						Role:    openai.ChatMessageRoleUser,
-						Content: instruction,
-					},
-				},
-			},
-		)
+		if !ss.consumeQuota(ctx, c, sl) {
+			return "", quota.ErrQuotaExceeded
+		}
+
+		generated, err := ss.generator.GenerateReply(ctx, c.AI.Request(instruction))
 
 		if err != nil {
 			return "", err
 		}
 
-		reply = strings.Trim(resp.Choices[0].Message.Content, `"`)
+		// every attempt is paid for, including the ones discarded for length
+		if err := usage.Record(ss.a.mongo, sl.ChatID, sl.ID, attempt, generated); err != nil {
+			ss.a.logger.Warn(
+				"unable to record generation usage",
+				zap.String("shillID", sl.ID.Hex()),
+				zap.Error(err),
+			)
+		}
+
+		reply = strings.Trim(generated.Text, `"`)
+		rejected = false
+
+		// check character limit was respected, counted the way X counts it
+		if !twittertext.Valid(reply, maxChars) {
+			attempt++
+			continue
+		}
+
+		// a single offensive reply can get the poster's account suspended so
+		// replies failing moderation are never returned
+		if !ss.moderate(ctx, c, sl, attempt, reply) {
+			rejected = true
+			attempt++
+			continue
+		}
 
-		// check character limit was
-		if len(reply) > maxChars {
+		// near identical replies in the same raid get flagged as spam, the last
+		// attempt is used regardless
+		similar := similarity.Similar(reply, earlier, duplicateThreshold())
+		if len(similar) > 0 && attempt < maxAttempts {
+			ss.a.logger.Info(
+				"reply too similar to an earlier reply, regenerating",
+				zap.String("shillID", sl.ID.Hex()),
+				zap.Float64("similarity", similar[0].Similarity),
+			)
+			instruction = differentInstruction(baseInstruction, similar)
 			attempt++
 			continue
 		}
 		break
 	}
 
-	return reply, nil
+	return brandsafety.ApplySuffix(c.BrandSafety, reply, maxChars), nil
+}
+
+// moderate - check the reply against the chat's brand-safety rules, banned words
+// and the moderation rules, rejections are logged and recorded. Provider errors
+// fail closed so the reply is regenerated, unless moderation.failOpen allows it
+// on the local checks alone.
+func (ss *shillService) moderate(ctx context.Context, c config.Config, sl *shillx.ShillLink, attempt int, reply string) bool {
+	result := brandsafety.Check(c.BrandSafety, reply)
+	if result.Allowed {
+		var err error
+		result, err = ss.moderator.Check(ctx, reply, c.BannedWords)
+		if err != nil {
+			failOpen := viper.GetBool("moderation.failOpen")
+			ss.a.logger.Error(
+				"an error occurred trying to moderate the reply",
+				zap.String("shillID", sl.ID.Hex()),
+				zap.Bool("allowed", failOpen),
+				zap.Error(err),
+			)
+			return failOpen
+		}
+	}
+
+	if result.Allowed {
+		return true
+	}
+
+	ss.a.logger.Warn(
+		"reply rejected by moderation",
+		zap.Int64("chatID", sl.ChatID),
+		zap.String("shillID", sl.ID.Hex()),
+		zap.Int("attempt", attempt),
+		zap.String("check", result.Check),
+		zap.String("reason", result.Reason),
+	)
+
+	if err := moderation.RecordRejection(ss.a.mongo, sl.ChatID, sl.ID, attempt, reply, result); err != nil {
+		ss.a.logger.Warn(
+			"unable to record moderation rejection",
+			zap.String("shillID", sl.ID.Hex()),
+			zap.Error(err),
+		)
+	}
+
+	return false
 }
 
-// aiInstruction
-func (ss *shillService) aiInstruction(sl *shillx.ShillLink) string {
+// chatConfig - the config of the chat the shill link was created in
+func (ss *shillService) chatConfig(sl *shillx.ShillLink) (config.Config, error) {
 	c, found, err := config.ConfigByChatID(ss.a.mongo, sl.ChatID)
 	if err != nil {
 		ss.a.logger.Error(
@@ -144,60 +370,110 @@ func (ss *shillService) aiInstruction(sl *shillx.ShillLink) string {
 			zap.Int64("chatID", sl.ChatID),
 			zap.Error(err),
 		)
-		return ""
+		return c, err
 	}
 
 	if !found {
 		ss.a.logger.Error(
 			"could not find config for chat ID",
 			zap.Int64("chatID", sl.ChatID),
+		)
+		return c, ErrConfigNotFound
+	}
+
+	return c, nil
+}
+
+// earlierReplies - replies already generated for the tweet, from any chat
+func (ss *shillService) earlierReplies(sl *shillx.ShillLink) []string {
+	if duplicateThreshold() <= 0 {
+		return nil
+	}
+
+	shills, err := shillx.ShillsByTweetID(ss.a.mongo, sl.TweetID, duplicateLookback())
+	if err != nil {
+		ss.a.logger.Warn(
+			"unable to fetch earlier replies for duplicate check",
+			zap.String("tweetID", sl.TweetID),
 			zap.Error(err),
 		)
-		return ""
+		return nil
 	}
 
-	if sl.ReplyType == shillx.REPLY_TYPE_TROLL {
-		return ss.aiInstructionTroll(c)
+	var replies []string
+	for _, s := range shills {
+		replies = append(replies, s.Replies()...)
 	}
 
-	return ss.aiInstructionShill(c)
+	return replies
 }
 
-// aiInstructionShill
-func (ss *shillService) aiInstructionShill(c config.Config) string {
-	instruction := `You are a crypto degen and an enthusiast of a new memecoin called %s. 
-	You love to reply to tweets related to crypto and use the opportunity to promote %s and it's awesome community.
-	It's community as describes itself as %s.
-	You are cheeky and are keen to mention the massive gains %s will bring.  
-	When you respond to tweets don't mention memes.  You can promote %s only and can disparage other coins in a friendly way.
-	When you create tweets you should try and include the hashtags "%s" and aim to keep the number of hashtags to a maxiumum of four but ideally keep to two.
-	When you create tweets you should try and include the cashtags "%s" in the response.`
+// duplicateThreshold - similarity from 0 to 1 above which a reply is regenerated,
+// configured by duplicates.threshold, 0 disables the check
+func duplicateThreshold() float64 {
+	if !viper.IsSet("duplicates.threshold") {
+		return defaultDuplicateThreshold
+	}
 
-	return fmt.Sprintf(
-		instruction,
-		c.Token,
-		c.Token,
-		c.Community,
-		c.Token,
-		c.Token,
-		c.Hashtags,
-		c.Cashtags,
-	)
+	return viper.GetFloat64("duplicates.threshold")
 }
 
-// aiInstructionTroll
-func (ss *shillService) aiInstructionTroll(c config.Config) string {
+// duplicateLookback - number of earlier replies to the tweet compared against
+func duplicateLookback() int64 {
+	lookback := viper.GetInt64("duplicates.lookback")
+	if lookback <= 0 {
+		lookback = defaultDuplicateLookback
+	}
 
-	instruction := `You are a twitter troll who enjoys making fun of other users tweets.
-	You can verge on being rude but always keep responses light hearted.
-	You can disparage others but in a jokey way.
-	You never promote harm, racism, homophobia, transphobia or have any strong political views.
-	When you create tweets you should try and include the hashtags "%s" and aim to keep the number of hashtags to a maxiumum of four but ideally keep to two.
-	When you create tweets you should try and include the cashtags "%s" in the response.`
+	return lookback
+}
 
-	return fmt.Sprintf(
-		instruction,
-		c.Hashtags,
-		c.Cashtags,
-	)
+// differentInstruction - ask for a reply clearly different from the most similar earlier replies
+func differentInstruction(instruction string, similar []similarity.Match) string {
+	if len(similar) > maxDifferentExamples {
+		similar = similar[:maxDifferentExamples]
+	}
+
+	var hint strings.Builder
+	hint.WriteString(instruction)
+	hint.WriteString("\n\nOther people have already replied to this tweet with the following. Your reply must be clearly different from these in wording, structure and angle:")
+	for _, m := range similar {
+		fmt.Fprintf(&hint, "\n- '%s'", m.Text)
+	}
+
+	return hint.String()
+}
+
+// replyLanguage - name of the language to reply in, empty when the tweet's language
+// couldn't be detected and the model should follow the tweet
+func replyLanguage(c config.Config, sl *shillx.ShillLink) string {
+	l, ok := language.ForTweet(sl.TweetText, c.Language.Preferred, c.Language.Forced)
+	if !ok {
+		return ""
+	}
+
+	return l.Name
+}
+
+// aiInstruction - render the chat's prompt template for the shill link
+func (ss *shillService) aiInstruction(c config.Config, sl *shillx.ShillLink, charLimit int, style string) (string, error) {
+	instruction, err := prompt.Render(ss.a.mongo, prompt.Data{
+		Config:    c,
+		ReplyType: sl.ReplyType,
+		TweetText: sl.TweetText,
+		CharLimit: charLimit,
+		Style:     style,
+		Language:  replyLanguage(c, sl),
+	})
+	if err != nil {
+		ss.a.logger.Error(
+			"an error occurred trying to render the prompt template",
+			zap.Int64("chatID", sl.ChatID),
+			zap.String("replyType", sl.ReplyType),
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	return instruction, nil
 }
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/shillx"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/llm"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/moderation"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/quota"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+const (
+	testChatID  = -1001234567890
+	testTweetID = "1234567890"
+	testReply   = "gm to everyone holding $TEST #TEST"
+	testModel   = "test-model"
+)
+
+// newTestShillService - a shill service backed by the mock deployment and generating
+// replies with generator
+func newTestShillService(mt *mtest.T, generator llm.ReplyGenerator) *shillService {
+	moderator, err := moderation.NewModerator()
+	if err != nil {
+		mt.Fatal(err)
+	}
+
+	counter := storage.NewMemoryCounter()
+	ss := &shillService{
+		a: &Api{
+			logger: zap.NewNop(),
+			mongo:  &storage.Mongo{Client: mt.Client},
+		},
+		moderator: moderator,
+		quota:     quota.NewQuota(counter),
+		limiter:   quota.NewRateLimiter(counter),
+		cache:     storage.NewMemoryStateStore(),
+	}
+	ss.SetGenerator(generator)
+
+	return ss
+}
+
+func TestCreateTwitterReply(t *testing.T) {
+	viper.Set("mongo.DB", "test")
+	viper.Set("llm.model", testModel)
+	defer viper.Set("mongo.DB", "")
+	defer viper.Set("llm.model", "")
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("reply is generated and its usage recorded", func(mt *mtest.T) {
+		generator := llm.NewFakeGenerator(testReply)
+		ss := newTestShillService(mt, generator)
+
+		shillLinkID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			// the shill link
+			mtest.CreateCursorResponse(0, "test.shillLink", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: shillLinkID},
+				{Key: "chatId", Value: int64(testChatID)},
+				{Key: "tweetId", Value: testTweetID},
+				{Key: "tweetLink", Value: "https://x.com/someone/status/" + testTweetID},
+				{Key: "tweetText", Value: "What's everyone buying this week?"},
+				{Key: "replyType", Value: shillx.REPLY_TYPE_SHILL},
+			}),
+			// the chat's config
+			mtest.CreateCursorResponse(0, "test.config", mtest.FirstBatch, bson.D{
+				{Key: "chatId", Value: int64(testChatID)},
+				{Key: "token", Value: "TEST"},
+				{Key: "hashtags", Value: "#TEST"},
+				{Key: "cashtags", Value: "$TEST"},
+			}),
+			// no prompt template of the chat's own
+			mtest.CreateCursorResponse(0, "test.promptTemplate", mtest.FirstBatch),
+			// no earlier replies to the tweet
+			mtest.CreateCursorResponse(0, "test.shill", mtest.FirstBatch),
+			// the usage then the reply are stored
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/shill/"+shillLinkID.Hex(), nil), rec)
+		c.SetParamNames("shillID")
+		c.SetParamValues(shillLinkID.Hex())
+
+		if err := ss.createTwitterReply(c); err != nil {
+			mt.Fatal(err)
+		}
+
+		if rec.Code != http.StatusFound {
+			mt.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusFound, rec.Body.String())
+		}
+
+		if got, want := rec.Header().Get(echo.HeaderLocation), tweetIntentURL(testTweetID, testReply); got != want {
+			mt.Errorf("redirected to %q, want %q", got, want)
+		}
+
+		if generator.Calls() != 1 {
+			mt.Errorf("generated %d replies, want 1", generator.Calls())
+		}
+
+		usage := insertedDocuments(mt, "usage")
+		if len(usage) != 1 {
+			mt.Fatalf("recorded usage %d times, want 1", len(usage))
+		}
+
+		u := usage[0]
+		if chatID := u.Lookup("chatId").Int64(); chatID != testChatID {
+			mt.Errorf("usage recorded for chat %d, want %d", chatID, testChatID)
+		}
+
+		if id := u.Lookup("shillLinkId").ObjectID(); id != shillLinkID {
+			mt.Errorf("usage recorded for shill link %s, want %s", id.Hex(), shillLinkID.Hex())
+		}
+
+		if model := u.Lookup("model").StringValue(); model != testModel {
+			mt.Errorf("usage recorded for model %q, want %q", model, testModel)
+		}
+
+		if tokens := u.Lookup("promptTokens").Int32(); tokens <= 0 {
+			mt.Errorf("recorded %d prompt tokens, want the instruction's", tokens)
+		}
+
+		if tokens, want := int(u.Lookup("completionTokens").Int32()), len(strings.Fields(testReply)); tokens != want {
+			mt.Errorf("recorded %d completion tokens, want %d", tokens, want)
+		}
+
+		shills := insertedDocuments(mt, "shill")
+		if len(shills) != 1 || shills[0].Lookup("reply").StringValue() != testReply {
+			mt.Errorf("stored shills %v, want the generated reply", shills)
+		}
+	})
+}
+
+// insertedDocuments - documents the client sent to be inserted into collection
+func insertedDocuments(mt *mtest.T, collection string) []bson.Raw {
+	var documents []bson.Raw
+	for _, e := range mt.GetAllStartedEvents() {
+		if e.CommandName != "insert" || e.Command.Lookup("insert").StringValue() != collection {
+			continue
+		}
+
+		values, err := e.Command.Lookup("documents").Array().Values()
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		for _, v := range values {
+			documents = append(documents, v.Document())
+		}
+	}
+
+	return documents
+}
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/shillx"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/moderation"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/quota"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	minVariants = 3
+	maxVariants = 5
+)
+
+var (
+	// variantStyles - each variant is written in a different style so the
+	// choices are distinct, the picked style is recorded on the shill
+	variantStyles = []string{
+		"witty and playful",
+		"bold and confident",
+		"informative",
+		"short and punchy",
+		"casual and friendly",
+	}
+
+	variantsPage = template.Must(template.New("variants").Parse(variantsPageHTML))
+)
+
+type variantsPageData struct {
+	TweetLink     string
+	RegenerateURL string
+	Variants      []variantView
+}
+
+type variantView struct {
+	Style   string
+	Reply   string
+	PostURL string
+}
+
+// variantCount - number of variants to offer from shill.variants, 1 when disabled
+func variantCount() int {
+	n := viper.GetInt("shill.variants")
+	if n <= 1 {
+		return 1
+	}
+
+	if n < minVariants {
+		return minVariants
+	}
+
+	if n > maxVariants {
+		return maxVariants
+	}
+
+	return n
+}
+
+// regenerateVariants
+func (ss *shillService) regenerateVariants(c echo.Context) error {
+	shillID := c.Param("shillID")
+
+	sl, found, err := shillx.ShillLinkByID(ss.a.mongo, shillID)
+	if err != nil {
+		ss.a.logger.Error(
+			"could not fetch shill link",
+			zap.String("shillID", shillID),
+			zap.Error(err),
+		)
+		return ReturnError(c, err)
+	}
+
+	if !found {
+		return ReturnNotFound(c, ErrShillNotFound)
+	}
+
+	return ss.renderVariants(c, sl, visitorID(c))
+}
+
+// pickVariant - record the chosen variant then send the user to post it. Picks are
+// POSTed from the page so link prefetchers and unfurlers can't record them
+func (ss *shillService) pickVariant(c echo.Context) error {
+	shillID := c.Param("shillID")
+	replyID := c.Param("replyID")
+
+	// malformed ids and indexes can't name a variant so aren't looked up
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		return ReturnNotFound(c, ErrVariantNotFound)
+	}
+
+	if _, err := primitive.ObjectIDFromHex(replyID); err != nil {
+		return ReturnNotFound(c, ErrVariantNotFound)
+	}
+
+	s, found, err := shillx.ShillByID(ss.a.mongo, replyID)
+	if err != nil {
+		ss.a.logger.Error(
+			"could not fetch shill",
+			zap.String("replyID", replyID),
+			zap.Error(err),
+		)
+		return ReturnError(c, err)
+	}
+
+	if !found || s.ShillLinkID.Hex() != shillID || index < 0 || index >= len(s.Variants) {
+		return ReturnNotFound(c, ErrVariantNotFound)
+	}
+
+	if err := s.Pick(index); err != nil {
+		ss.a.logger.Warn(
+			"unable to record picked variant",
+			zap.String("replyID", replyID),
+			zap.Int("index", index),
+			zap.Error(err),
+		)
+	}
+
+	return c.Redirect(http.StatusSeeOther, tweetIntentURL(s.TweetID, s.Variants[index].Reply))
+}
+
+// renderVariants - generate a fresh set of variants and render the pick page, the
+// new set replaces any the visitor has cached
+func (ss *shillService) renderVariants(c echo.Context, sl *shillx.ShillLink, visitor string) error {
+	variants, err := ss.generateVariants(c.Request().Context(), sl, variantCount())
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		return ReturnQuotaReached(c)
+	}
+
+	if errors.Is(err, moderation.ErrRejected) {
+		return ReturnReplyRejected(c)
+	}
+
+	if err != nil {
+		return ReturnError(c, err)
+	}
+
+	s := shillx.NewShill(ss.a.mongo)
+	s.ShillLinkID = sl.ID
+	s.ChatID = sl.ChatID
+	s.TweetID = sl.TweetID
+	s.Variants = variants
+	if err := s.Insert(s); err != nil {
+		ss.a.logger.Error(
+			"unable to store generated shill variants",
+			zap.String("shillID", sl.ID.Hex()),
+			zap.Error(err),
+		)
+		return ReturnFatalError(c, err)
+	}
+	ss.cacheReply(sl, visitor, s)
+
+	return ss.renderVariantsPage(c, sl, s)
+}
+
+// renderVariantsPage
+func (ss *shillService) renderVariantsPage(c echo.Context, sl *shillx.ShillLink, s *shillx.Shill) error {
+	basePath := ss.a.BasePath() + shillServiceBasePath
+	data := variantsPageData{
+		TweetLink:     sl.TweetLink,
+		RegenerateURL: fmt.Sprintf("%s/%s/regenerate", basePath, sl.ID.Hex()),
+	}
+
+	for i, v := range s.Variants {
+		data.Variants = append(data.Variants, variantView{
+			Style:   v.Style,
+			Reply:   v.Reply,
+			PostURL: fmt.Sprintf("%s/%s/pick/%s/%d", basePath, sl.ID.Hex(), s.ID.Hex(), i),
+		})
+	}
+
+	var page bytes.Buffer
+	if err := variantsPage.Execute(&page, data); err != nil {
+		return ReturnFatalError(c, err)
+	}
+
+	return c.HTML(http.StatusOK, page.String())
+}
+
+// generateVariants - generate n replies in parallel, each in a different style and
+// charged to the quota separately. Variants past the quota are dropped
+func (ss *shillService) generateVariants(ctx context.Context, sl *shillx.ShillLink, n int) ([]shillx.ShillVariant, error) {
+	variants := make([]shillx.ShillVariant, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			style := variantStyles[i%len(variantStyles)]
+			reply, err := ss.generateReply(ctx, sl, style)
+			variants[i] = shillx.ShillVariant{Style: style, Reply: reply}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	// drop failed variants, only fail when none were generated
+	var generated []shillx.ShillVariant
+	for i, v := range variants {
+		if errs[i] != nil {
+			ss.a.logger.Warn(
+				"unable to generate variant",
+				zap.String("shillID", sl.ID.Hex()),
+				zap.String("style", v.Style),
+				zap.Error(errs[i]),
+			)
+			continue
+		}
+		generated = append(generated, v)
+	}
+
+	if len(generated) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return generated, nil
+}
+
+const variantsPageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Pick your reply</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; background: #000; color: #e7e9ea; margin: 0; padding: 16px; }
+main { max-width: 600px; margin: 0 auto; }
+h1 { font-size: 1.4em; }
+a { color: #1d9bf0; }
+.variant { border: 1px solid #2f3336; border-radius: 12px; padding: 12px 16px; margin: 12px 0; }
+.style { color: #71767b; font-size: 0.85em; text-transform: uppercase; }
+.reply { white-space: pre-wrap; margin: 8px 0 12px; }
+.button { display: inline-block; background: #1d9bf0; color: #fff; border: 0; border-radius: 9999px; padding: 8px 18px; font-weight: bold; font-size: 1em; text-decoration: none; cursor: pointer; }
+.secondary { background: #2f3336; }
+</style>
+</head>
+<body>
+<main>
+<h1>Pick your reply</h1>
+<p>Replying to <a href="{{.TweetLink}}" target="_blank" rel="noopener">{{.TweetLink}}</a></p>
+{{range .Variants}}
+<div class="variant">
+<div class="style">{{.Style}}</div>
+<div class="reply">{{.Reply}}</div>
+<form method="post" action="{{.PostURL}}">
+<button class="button" type="submit">Post</button>
+</form>
+</div>
+{{end}}
+<form method="post" action="{{.RegenerateURL}}">
+<button class="button secondary" type="submit">Regenerate</button>
+</form>
+</main>
+</body>
+</html>
+`
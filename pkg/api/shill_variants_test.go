@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func TestPickVariantMalformedIDs(t *testing.T) {
+	// without a mongo client any lookup would panic, malformed ids must not get that far
+	ss := &shillService{a: &Api{logger: zap.NewNop()}}
+	shillID := primitive.NewObjectID().Hex()
+
+	tests := map[string]struct {
+		replyID string
+		index   string
+	}{
+		"reply id isn't hex":         {"not-an-object-id", "0"},
+		"reply id is the wrong size": {"abc123", "0"},
+		"index isn't a number":       {primitive.NewObjectID().Hex(), "first"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+			c.SetParamNames("shillID", "replyID", "index")
+			c.SetParamValues(shillID, tt.replyID, tt.index)
+
+			if err := ss.pickVariant(c); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package brandsafety
+
+import (
+	"fmt"
+	"strings"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/moderation"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/twittertext"
+)
+
+const (
+	CHECK_FORBIDDEN_PHRASE = "forbiddenPhrase"
+	CHECK_COMPETITOR       = "competitor"
+
+	NFA_SUFFIX = "NFA"
+)
+
+// Check - reject replies using a forbidden phrase or naming a competitor, competitor
+// tokens match with or without a leading $ or #
+func Check(rules config.BrandSafety, reply string) moderation.Result {
+	if phrase, found := moderation.FindBannedWord(reply, rules.ForbiddenPhrases); found {
+		return moderation.Rejected(CHECK_FORBIDDEN_PHRASE, fmt.Sprintf("contains forbidden phrase %q", phrase))
+	}
+
+	var competitors []string
+	for _, competitor := range rules.Competitors {
+		competitors = append(competitors, strings.TrimLeft(competitor, "$#"))
+	}
+
+	if competitor, found := moderation.FindBannedWord(reply, competitors); found {
+		return moderation.Rejected(CHECK_COMPETITOR, fmt.Sprintf("mentions competitor %q", competitor))
+	}
+
+	return moderation.Allowed()
+}
+
+// ApplySuffix - add the NFA suffix when the chat wants it and the reply doesn't
+// already say it, the reply is shortened to make room within maxChars
+func ApplySuffix(rules config.BrandSafety, reply string, maxChars int) string {
+	if !rules.NFASuffix {
+		return reply
+	}
+
+	if _, found := moderation.FindBannedWord(reply, []string{NFA_SUFFIX}); found {
+		return reply
+	}
+
+	suffix := " " + NFA_SUFFIX
+	reply = twittertext.Truncate(reply, maxChars-twittertext.WeightedLength(suffix))
+
+	return reply + suffix
+}
@@ -2,6 +2,8 @@ package commandhandler
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -9,20 +11,64 @@ import (
 
 type CommandHandler interface {
 	Handle(ctx context.Context, b *bot.Bot, update *models.Update)
-	Cancelled(chatID int64) bool
-	Cancel(chatID int64)
-	Reset(ctx context.Context, b *bot.Bot, chatID int64)
-	Done(chatID int64) bool
+	Cancelled(sk SessionKey) bool
+	Cancel(ctx context.Context, b *bot.Bot, sk SessionKey)
+	Reset(ctx context.Context, b *bot.Bot, sk SessionKey)
+	Done(sk SessionKey) bool
+}
+
+// TweetLinkHandler - command handlers that can start with the tweet link already known
+type TweetLinkHandler interface {
+	StartWithTweetLink(ctx context.Context, b *bot.Bot, sk SessionKey, user models.User, tweetLink string)
+}
+
+// TimeoutHandler - command handlers with their own idle timeout, see Conversation.WithTimeout
+type TimeoutHandler interface {
+	Timeout() time.Duration
+}
+
+// ButtonHandler - command handlers whose prompts have buttons, see Conversation.Press
+type ButtonHandler interface {
+	Press(ctx context.Context, b *bot.Bot, bc ButtonCallback) bool
+}
+
+// SessionKey - identifies one user's conversation with the bot in a chat
+type SessionKey struct {
+	ChatID int64 `json:"chatId"`
+	UserID int64 `json:"userId"`
+}
+
+// NewSessionKey
+func NewSessionKey(chatID int64, userID int64) SessionKey {
+	return SessionKey{
+		ChatID: chatID,
+		UserID: userID,
+	}
+}
+
+// SessionKeyFromMessage - messages without a sender, e.g. channel posts, share the chat wide session
+func SessionKeyFromMessage(message *models.Message) SessionKey {
+	sk := SessionKey{ChatID: message.Chat.ID}
+	if message.From != nil {
+		sk.UserID = message.From.ID
+	}
+
+	return sk
+}
+
+// String
+func (sk SessionKey) String() string {
+	return fmt.Sprintf("%d:%d", sk.ChatID, sk.UserID)
 }
 
 type Command struct{}
 
 func (c *Command) Handle(ctx context.Context, b *bot.Bot, update *models.Update) {}
-func (c *Command) Cancelled(chatId int64) bool {
+func (c *Command) Cancelled(sk SessionKey) bool {
 	return false
 }
-func (c *Command) Cancel(chatId int64)                                 {}
-func (c *Command) Reset(ctx context.Context, b *bot.Bot, chatID int64) {}
-func (c *Command) Done(chatId int64) bool {
+func (c *Command) Cancel(ctx context.Context, b *bot.Bot, sk SessionKey) {}
+func (c *Command) Reset(ctx context.Context, b *bot.Bot, sk SessionKey)  {}
+func (c *Command) Done(sk SessionKey) bool {
 	return true
 }
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/llm"
+)
+
+const (
+	COMMAND_SET_AI_TEMPERATURE      = "setAITemperature"
+	COMMAND_SET_AI_MAX_TOKENS       = "setAIMaxTokens"
+	COMMAND_SET_AI_PRESENCE_PENALTY = "setAIPresencePenalty"
+)
+
+// promptAISettings - show the chat's generation parameters
+func (cch *configCommandHandler) promptAISettings(ctx context.Context, s *session, kb *keyboard) (string, error) {
+	c, err := cch.configByChatID(s.Key.ChatID)
+	if err != nil {
+		return "", err
+	}
+
+	kb.Row().
+		Button(s.T(i18n.ConfigButtonModel), gotoStep(STEP_AI_MODEL)).
+		Button(s.T(i18n.ConfigButtonTemperature), gotoStep(COMMAND_SET_AI_TEMPERATURE)).
+		Row().
+		Button(s.T(i18n.ConfigButtonMaxTokens), gotoStep(COMMAND_SET_AI_MAX_TOKENS)).
+		Button(s.T(i18n.ConfigButtonPresencePenalty), gotoStep(COMMAND_SET_AI_PRESENCE_PENALTY)).
+		Row().
+		Button(s.T(i18n.ConfigButtonResetDefaults), cch.changeAISettings(func(ai *config.AISettings) error {
+			*ai = config.AISettings{}
+			return nil
+		}))
+
+	return s.T(
+		i18n.ConfigAISettings,
+		displayAIModel(s, c.AI.Model),
+		displayAIFloat(s, c.AI.Temperature),
+		displayAIInt(s, c.AI.MaxTokens),
+		displayAIFloat(s, c.AI.PresencePenalty),
+	), nil
+}
+
+// displayAIModel
+func displayAIModel(s *session, model string) string {
+	if model == "" || !llm.ModelAllowed(model) {
+		return s.T(i18n.ConfigDefaultModel, llm.Model())
+	}
+
+	return model
+}
+
+// displayAIFloat
+func displayAIFloat(s *session, value *float32) string {
+	if value == nil {
+		return s.T(i18n.ConfigDefault)
+	}
+
+	return strconv.FormatFloat(float64(*value), 'f', -1, 32)
+}
+
+// displayAIInt
+func displayAIInt(s *session, value int) string {
+	if value == 0 {
+		return s.T(i18n.ConfigDefault)
+	}
+
+	return strconv.Itoa(value)
+}
+
+// promptAIModel - list the models from the llm.models allowlist, the first button
+// resets the chat to the default model
+func (cch *configCommandHandler) promptAIModel(ctx context.Context, s *session, kb *keyboard) (string, error) {
+	kb.Row().Button(s.T(i18n.ConfigButtonDefaultModel, llm.Model()), cch.selectAIModel(""))
+
+	for _, model := range llm.Models() {
+		kb.Row().Button(model, cch.selectAIModel(model))
+	}
+
+	return s.T(i18n.ConfigAIModelPrompt), nil
+}
+
+// selectAIModel
+func (cch *configCommandHandler) selectAIModel(model string) action {
+	return cch.changeAISettings(func(ai *config.AISettings) error {
+		if err := config.ValidateModel(model); err != nil {
+			return err
+		}
+
+		ai.Model = model
+		return nil
+	})
+}
+
+// aiTemperatureStep
+func (cch *configCommandHandler) aiTemperatureStep() step {
+	return cch.aiSettingStep(
+		func(s *session) string {
+			return s.T(i18n.ConfigAITemperaturePrompt, config.MinTemperature, config.MaxTemperature)
+		},
+		func(ai *config.AISettings, value string) error {
+			temperature, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return err
+			}
+			if err := config.ValidateTemperature(float32(temperature)); err != nil {
+				return err
+			}
+			t := float32(temperature)
+			ai.Temperature = &t
+			return nil
+		},
+		cch.changeAISettings(func(ai *config.AISettings) error {
+			ai.Temperature = nil
+			return nil
+		}),
+	)
+}
+
+// aiMaxTokensStep
+func (cch *configCommandHandler) aiMaxTokensStep() step {
+	return cch.aiSettingStep(
+		func(s *session) string {
+			return s.T(i18n.ConfigAIMaxTokensPrompt, config.MinMaxTokens, config.MaxMaxTokens)
+		},
+		func(ai *config.AISettings, value string) error {
+			maxTokens, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if err := config.ValidateMaxTokens(maxTokens); err != nil {
+				return err
+			}
+			ai.MaxTokens = maxTokens
+			return nil
+		},
+		nil,
+	)
+}
+
+// aiPresencePenaltyStep
+func (cch *configCommandHandler) aiPresencePenaltyStep() step {
+	return cch.aiSettingStep(
+		func(s *session) string {
+			return s.T(i18n.ConfigAIPresencePenaltyPrompt, config.MinPresencePenalty, config.MaxPresencePenalty)
+		},
+		func(ai *config.AISettings, value string) error {
+			presencePenalty, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return err
+			}
+			if err := config.ValidatePresencePenalty(float32(presencePenalty)); err != nil {
+				return err
+			}
+			p := float32(presencePenalty)
+			ai.PresencePenalty = &p
+			return nil
+		},
+		cch.changeAISettings(func(ai *config.AISettings) error {
+			ai.PresencePenalty = nil
+			return nil
+		}),
+	)
+}
+
+// aiSettingStep - a prompt for one AI setting, apply parses and sets the value so
+// also validates the reply against a copy of the settings. clear, when set, puts
+// the setting back to its default
+func (cch *configCommandHandler) aiSettingStep(prompt func(s *session) string, apply func(ai *config.AISettings, value string) error, clear action) step {
+	return settingStep(
+		prompt,
+		func(s *session, message *models.Message) error {
+			if apply(&config.AISettings{}, commandhandler.Text(message)) != nil {
+				return errors.New(s.T(i18n.ConfigInvalidValue))
+			}
+
+			return nil
+		},
+		func(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+			return cch.updateConfig(s, STEP_AI_SETTINGS, func(c *config.Config) error {
+				return apply(&c.AI, commandhandler.Text(message))
+			})
+		},
+		clear,
+	)
+}
+
+// changeAISettings - apply a change to the chat's AI settings and show them again
+func (cch *configCommandHandler) changeAISettings(apply func(ai *config.AISettings) error) action {
+	return cch.change(STEP_AI_SETTINGS, func(c *config.Config) error {
+		return apply(&c.AI)
+	})
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/moderation"
+)
+
+const (
+	COMMAND_SET_BANNED_WORDS = "setBannedWords"
+)
+
+// bannedWordsStep
+func (cch *configCommandHandler) bannedWordsStep() step {
+	return settingStep(
+		func(s *session) string {
+			return s.T(i18n.ConfigBannedWordsPrompt)
+		},
+		func(s *session, message *models.Message) error {
+			if config.ValidateWordList(moderation.ParseWords(message.Text)) != nil {
+				return errors.New(s.T(i18n.ConfigInvalidBannedWords))
+			}
+
+			return nil
+		},
+		func(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+			return cch.updateConfig(s, STEP_MAIN_MENU, func(c *config.Config) error {
+				c.BannedWords = moderation.ParseWords(message.Text)
+				return nil
+			})
+		},
+		cch.change(STEP_MAIN_MENU, func(c *config.Config) error {
+			c.BannedWords = nil
+			return nil
+		}),
+	)
+}
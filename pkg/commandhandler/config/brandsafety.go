@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"html"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/moderation"
+)
+
+const (
+	COMMAND_SET_FORBIDDEN_PHRASES = "setForbiddenPhrases"
+	COMMAND_SET_COMPETITORS       = "setCompetitors"
+)
+
+// promptBrandSafety - show the chat's brand-safety rules
+func (cch *configCommandHandler) promptBrandSafety(ctx context.Context, s *session, kb *keyboard) (string, error) {
+	c, err := cch.configByChatID(s.Key.ChatID)
+	if err != nil {
+		return "", err
+	}
+
+	kb.Row().
+		Button(s.T(i18n.ConfigButtonForbiddenPhrases), gotoStep(COMMAND_SET_FORBIDDEN_PHRASES)).
+		Button(s.T(i18n.ConfigButtonCompetitors), gotoStep(COMMAND_SET_COMPETITORS)).
+		Row().
+		Button(s.T(i18n.ConfigButtonNFASuffix, displayConfigToggle(s, c.BrandSafety.NFASuffix)), cch.changeBrandSafety(func(bs *config.BrandSafety) {
+			bs.NFASuffix = !bs.NFASuffix
+		}))
+
+	return s.T(
+		i18n.ConfigBrandSafety,
+		displayWordList(s, c.BrandSafety.ForbiddenPhrases),
+		displayWordList(s, c.BrandSafety.Competitors),
+		displayConfigToggle(s, c.BrandSafety.NFASuffix),
+	), nil
+}
+
+// displayWordList
+func displayWordList(s *session, words []string) string {
+	if len(words) == 0 {
+		return s.T(i18n.ConfigNone)
+	}
+
+	return html.EscapeString(strings.Join(words, ", "))
+}
+
+// forbiddenPhrasesStep
+func (cch *configCommandHandler) forbiddenPhrasesStep() step {
+	return cch.brandSafetyListStep(i18n.ConfigForbiddenPhrasesPrompt, func(bs *config.BrandSafety) *[]string {
+		return &bs.ForbiddenPhrases
+	})
+}
+
+// competitorsStep
+func (cch *configCommandHandler) competitorsStep() step {
+	return cch.brandSafetyListStep(i18n.ConfigCompetitorsPrompt, func(bs *config.BrandSafety) *[]string {
+		return &bs.Competitors
+	})
+}
+
+// brandSafetyListStep - a prompt for one of the brand-safety lists, clear empties it
+func (cch *configCommandHandler) brandSafetyListStep(prompt i18n.Key, list func(bs *config.BrandSafety) *[]string) step {
+	return settingStep(
+		func(s *session) string { return s.T(prompt) },
+		func(s *session, message *models.Message) error {
+			if config.ValidateWordList(moderation.ParseWords(message.Text)) != nil {
+				return errors.New(s.T(i18n.ConfigInvalidValue))
+			}
+
+			return nil
+		},
+		func(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+			return cch.updateConfig(s, STEP_BRAND_SAFETY, func(c *config.Config) error {
+				*list(&c.BrandSafety) = moderation.ParseWords(message.Text)
+				return nil
+			})
+		},
+		cch.changeBrandSafety(func(bs *config.BrandSafety) {
+			*list(bs) = nil
+		}),
+	)
+}
+
+// changeBrandSafety - apply a change to the chat's brand-safety rules and show them again
+func (cch *configCommandHandler) changeBrandSafety(apply func(bs *config.BrandSafety)) action {
+	return cch.change(STEP_BRAND_SAFETY, func(c *config.Config) error {
+		apply(&c.BrandSafety)
+		return nil
+	})
+}
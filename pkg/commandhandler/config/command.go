@@ -7,41 +7,51 @@ import (
 	"os"
 	"regexp"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/persona"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
-	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/tghelper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 const (
+	COMMAND_CONFIG             = "config"
 	COMMAND_SET_TOKEN_NAME     = "setTokenName"
 	COMMAND_SET_HASH_TAGS      = "setHashtags"
 	COMMAND_SET_CASH_TAGS      = "setCashtags"
 	COMMAND_SET_COMMUNITY_DESC = "setCommunityDescription"
 	COMMAND_SET_CANCEL         = "cancel"
-	COMMAND_NONE               = "none"
+
+	STEP_MAIN_MENU    = "mainMenu"
+	STEP_PERSONAS     = "personas"
+	STEP_AI_SETTINGS  = "aiSettings"
+	STEP_AI_MODEL     = "aiModel"
+	STEP_BRAND_SAFETY = "brandSafety"
+	STEP_LANGUAGE     = "language"
+	STEP_LOCALE       = "locale"
+
+	sessionTimeout = 15 * time.Minute
 )
 
-var (
-	state      = make(map[int64]configHandlerState)
-	stateMutex = &sync.RWMutex{}
+type (
+	session  = commandhandler.Session[configData]
+	keyboard = commandhandler.Keyboard[configData]
+	step     = commandhandler.Step[configData]
+	action   = commandhandler.Action[configData]
 )
 
-type configHandlerState struct {
-	activeCommand string
-	lastPrompts   []*models.Message
-	done          bool
-}
+// configData - config sessions keep nothing of their own, every change is saved
+// to the chat's config straight away
+type configData struct{}
 
 type configCommandHandler struct {
-	commandhandler.Command
-	tgh    tghelper.TGHelper
+	*commandhandler.Conversation[configData]
 	logger *zap.Logger
 	mongo  *storage.Mongo
 }
@@ -57,558 +67,310 @@ func NewConfigCommandHandler() commandhandler.CommandHandler {
 	))
 	defer logger.Sync()
 
-	return &configCommandHandler{
+	cch := &configCommandHandler{
 		logger: logger,
 		mongo:  storage.NewMongo(),
 	}
-}
+	cch.Conversation = cch.newConversation(logger)
 
-// Handle
-func (cch *configCommandHandler) Handle(ctx context.Context, b *bot.Bot, update *models.Update) {
-	stateMutex.Lock()
-	defer stateMutex.Unlock()
-
-	cch.tgh = tghelper.NewTGHelper(b, cch.logger)
+	return cch
+}
 
-	chatID := update.Message.Chat.ID
+// newConversation - menus are finished with Done or Back, prompts for a setting
+// return to the menu they were opened from
+func (cch *configCommandHandler) newConversation(logger *zap.Logger) *commandhandler.Conversation[configData] {
+	return commandhandler.NewConversation[configData](COMMAND_CONFIG, logger).
+		WithTimeout(sessionTimeout).
+		WithMongo(cch.mongo).
+		Step(STEP_MAIN_MENU, step{Prompt: cch.promptMainMenu, NoCancel: true}).
+		Step(STEP_PERSONAS, step{Prompt: cch.promptPersonas, NoCancel: true}).
+		Step(STEP_AI_SETTINGS, step{Prompt: cch.promptAISettings, NoCancel: true}).
+		Step(STEP_AI_MODEL, step{Prompt: cch.promptAIModel, NoCancel: true}).
+		Step(STEP_BRAND_SAFETY, step{Prompt: cch.promptBrandSafety, NoCancel: true}).
+		Step(STEP_LANGUAGE, step{Prompt: cch.promptLanguage, NoCancel: true}).
+		Step(STEP_LOCALE, step{Prompt: cch.promptLocale, NoCancel: true}).
+		Step(COMMAND_SET_TOKEN_NAME, settingStep(
+			func(s *session) string { return s.T(i18n.ConfigTokenNamePrompt) },
+			cch.validateTokenName,
+			cch.receiveTokenName,
+			nil,
+		)).
+		Step(COMMAND_SET_HASH_TAGS, settingStep(
+			func(s *session) string { return s.T(i18n.ConfigHashtagsPrompt) },
+			cch.validateHashtags,
+			cch.receiveHashTags,
+			cch.change(STEP_MAIN_MENU, func(c *config.Config) error {
+				c.Hashtags = ""
+				return nil
+			}),
+		)).
+		Step(COMMAND_SET_CASH_TAGS, settingStep(
+			func(s *session) string { return s.T(i18n.ConfigCashtagsPrompt) },
+			cch.validateCashtags,
+			cch.receiveCashTags,
+			cch.change(STEP_MAIN_MENU, func(c *config.Config) error {
+				c.Cashtags = ""
+				return nil
+			}),
+		)).
+		Step(COMMAND_SET_COMMUNITY_DESC, settingStep(
+			func(s *session) string { return s.T(i18n.ConfigCommunityPrompt) },
+			cch.validateCommnunityDescription,
+			cch.receiveCommunityDescription,
+			cch.change(STEP_MAIN_MENU, func(c *config.Config) error {
+				c.Community = ""
+				return nil
+			}),
+		)).
+		Step(COMMAND_SET_BANNED_WORDS, cch.bannedWordsStep()).
+		Step(COMMAND_SET_MANAGERS, cch.managersStep()).
+		Step(COMMAND_SET_FORBIDDEN_PHRASES, cch.forbiddenPhrasesStep()).
+		Step(COMMAND_SET_COMPETITORS, cch.competitorsStep()).
+		Step(COMMAND_SET_PREFERRED_LANGUAGES, cch.preferredLanguagesStep()).
+		Step(COMMAND_SET_FORCED_LANGUAGE, cch.forcedLanguageStep()).
+		Step(COMMAND_SET_AI_TEMPERATURE, cch.aiTemperatureStep()).
+		Step(COMMAND_SET_AI_MAX_TOKENS, cch.aiMaxTokensStep()).
+		Step(COMMAND_SET_AI_PRESENCE_PENALTY, cch.aiPresencePenaltyStep())
+}
 
-	chs, err := cch.state(chatID)
-	if err != nil {
-		cch.DisplayMainMenu(ctx, b, chatID)
-		return
-	}
+// settingStep - a prompt answered by typing the setting's value, clear adds a
+// button emptying it
+func settingStep(prompt func(s *session) string, validate func(s *session, message *models.Message) error, receive func(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error), clear action) step {
+	return step{
+		Prompt: func(ctx context.Context, s *session, kb *keyboard) (string, error) {
+			if clear != nil {
+				kb.Row().Button(s.T(i18n.ButtonClear), clear)
+			}
 
-	switch chs.activeCommand {
-	case COMMAND_SET_TOKEN_NAME:
-		cch.receiveTokenName(state[chatID], ctx, b, update)
-	case COMMAND_SET_HASH_TAGS:
-		cch.receiveHashTags(state[chatID], ctx, b, update)
-	case COMMAND_SET_CASH_TAGS:
-		cch.receiveCashTags(state[chatID], ctx, b, update)
-	case COMMAND_SET_COMMUNITY_DESC:
-		cch.receiveCommunityDescription(state[chatID], ctx, b, update)
-	default:
-		cch.DisplayMainMenu(ctx, b, chatID)
+			return prompt(s), nil
+		},
+		Validate: validate,
+		Receive:  receive,
 	}
-
 }
 
-// DisplayMainMenu
-func (cch *configCommandHandler) DisplayMainMenu(ctx context.Context, b *bot.Bot, chatID int64) {
-	chs := configHandlerState{
-		activeCommand: COMMAND_NONE,
-	}
-	cch.updateState(chatID, chs)
+// gotoStep
+func gotoStep(name string) action {
+	return commandhandler.Goto[configData](name)
+}
 
-	c, err := cch.configByChatID(chatID)
-	if err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
+// Handle - /config shows the main menu, again if it's already open. Other messages
+// only answer the open prompt.
+func (cch *configCommandHandler) Handle(ctx context.Context, b *bot.Bot, update *models.Update) {
+	if commandhandler.CommandName(update.Message) != COMMAND_CONFIG {
+		cch.Receive(ctx, b, update)
 		return
 	}
 
-	message := `Current configuration:
-
-<b>Token name:</b> %s
-<b>Hashtag(s):</b> %s
-<b>Cashtag(s):</b> %s
-<b>Community:</b> %s`
-
-	message = fmt.Sprintf(
-		message,
-		cch.displayConfigValue(c.Token),
-		cch.displayConfigValue(c.Hashtags),
-		cch.displayConfigValue(c.Cashtags),
-		cch.displayConfigValue(c.Community),
-	)
-
-	sendMessageParams := &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ParseMode:   models.ParseModeHTML,
-		ReplyMarkup: cch.configKeyboard(b),
-	}
-
-	chs.lastPrompts, _ = cch.tgh.DeleteAllMessages(ctx, chatID, chs.lastPrompts)
+	// the locale is resolved from the chat's config when the main menu is shown,
+	// falling back to the language of the member who opened /config
+	user := *update.Message.From
+	s := commandhandler.NewSession[configData](commandhandler.SessionKeyFromMessage(update.Message), user, i18n.Resolve("", user.LanguageCode))
+	cch.Start(ctx, b, s, gotoStep(STEP_MAIN_MENU))
+}
 
-	prompt, err := b.SendMessage(ctx, sendMessageParams)
+// promptMainMenu
+func (cch *configCommandHandler) promptMainMenu(ctx context.Context, s *session, kb *keyboard) (string, error) {
+	c, err := cch.configByChatID(s.Key.ChatID)
 	if err != nil {
-		cch.logger.Error(
-			"failed to send config main menu",
-			zap.Int64("chatID", chatID),
-			zap.Error(err),
-		)
-	}
-
-	chs.lastPrompts = append(chs.lastPrompts, prompt)
+		return "", err
+	}
+
+	s.Locale = i18n.Resolve(c.Locale, s.User.LanguageCode)
+
+	kb.Row().
+		Button(s.T(i18n.ConfigButtonTokenName), gotoStep(COMMAND_SET_TOKEN_NAME)).
+		Button(s.T(i18n.ConfigButtonCommunity), gotoStep(COMMAND_SET_COMMUNITY_DESC)).
+		Row().
+		Button(s.T(i18n.ConfigButtonHashtags), gotoStep(COMMAND_SET_HASH_TAGS)).
+		Button(s.T(i18n.ConfigButtonCashtags), gotoStep(COMMAND_SET_CASH_TAGS)).
+		Row().
+		Button(s.T(i18n.ConfigButtonAutoDetectLinks, displayConfigToggle(s, c.AutoDetectLinks)), cch.change(STEP_MAIN_MENU, func(c *config.Config) error {
+			c.AutoDetectLinks = !c.AutoDetectLinks
+			return nil
+		})).
+		Button(s.T(i18n.ConfigButtonPersonas), gotoStep(STEP_PERSONAS)).
+		Row().
+		Button(s.T(i18n.ConfigButtonAISettings), gotoStep(STEP_AI_SETTINGS)).
+		Button(s.T(i18n.ConfigButtonReplyLanguage), gotoStep(STEP_LANGUAGE)).
+		Row().
+		Button(s.T(i18n.ConfigButtonBannedWords), gotoStep(COMMAND_SET_BANNED_WORDS)).
+		Button(s.T(i18n.ConfigButtonBrandSafety), gotoStep(STEP_BRAND_SAFETY)).
+		Row().
+		Button(s.T(i18n.ConfigButtonManagers), gotoStep(COMMAND_SET_MANAGERS)).
+		Button(s.T(i18n.ConfigButtonLocale), gotoStep(STEP_LOCALE)).
+		Row().
+		Button(s.T(i18n.ButtonDone), gotoStep(commandhandler.STEP_END))
+
+	return s.T(
+		i18n.ConfigMainMenu,
+		displayConfigValue(s, c.Token),
+		displayConfigValue(s, c.Hashtags),
+		displayConfigValue(s, c.Cashtags),
+		displayConfigValue(s, c.Community),
+		displayConfigToggle(s, c.AutoDetectLinks),
+		displayEnabledPersonas(s, c),
+		displayWordList(s, c.BannedWords),
+		displayManagers(s, c.Managers),
+		displayLocale(s, c.Locale),
+	), nil
 }
 
 // displayConfigValue
-func (cch *configCommandHandler) displayConfigValue(value string) string {
+func displayConfigValue(s *session, value string) string {
 	if value == "" {
-		value = "<i>Not set</i>"
+		value = s.T(i18n.ConfigNotSet)
 	}
 
 	return value
 }
 
-// Reset
-func (cch *configCommandHandler) Reset(ctx context.Context, b *bot.Bot, chatID int64) {}
-
-// Cancel
-func (cch *configCommandHandler) Cancel(chatID int64) {
-	chs, err := cch.state(chatID)
-	if err != nil {
-		return
+// displayConfigToggle
+func displayConfigToggle(s *session, value bool) string {
+	if value {
+		return s.T(i18n.On)
 	}
 
-	chs.activeCommand = COMMAND_NONE
-	chs.done = false
-	cch.updateState(chatID, chs)
+	return s.T(i18n.Off)
 }
 
-// Done
-func (cch *configCommandHandler) Done(chatID int64) bool {
-	chs, err := cch.state(chatID)
-	if err != nil {
-		return false
+// displayEnabledPersonas
+func displayEnabledPersonas(s *session, c config.Config) string {
+	var enabled []string
+	for _, p := range persona.All() {
+		if c.PersonaEnabled(p.Name) {
+			enabled = append(enabled, "/"+p.Command)
+		}
 	}
 
-	return chs.done
-}
-
-// updateState
-func (cch *configCommandHandler) updateState(chatID int64, chs configHandlerState) {
-	state[chatID] = chs
-}
-
-// onBack
-func (cch *configCommandHandler) onBack(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	cch.DisplayMainMenu(ctx, b, chatID)
-}
-
-// onCancel
-func (cch *configCommandHandler) onCancel(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	chs, err := cch.state(chatID)
-	if err != nil {
-		return
+	if len(enabled) == 0 {
+		return s.T(i18n.ConfigNone)
 	}
 
-	chs.lastPrompts, _ = cch.tgh.DeleteAllMessages(ctx, chatID, chs.lastPrompts)
-	cch.Cancel(chatID)
+	return strings.Join(enabled, " ")
 }
 
-// onConfigDone
-func (cch *configCommandHandler) onConfigDone(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	chs, err := cch.state(chatID)
-	if err != nil {
-		return
+// change - an action applying a change to the chat's config and showing next
+func (cch *configCommandHandler) change(next string, apply func(c *config.Config) error) action {
+	return func(ctx context.Context, b *bot.Bot, s *session) (string, error) {
+		return cch.updateConfig(s, next, apply)
 	}
-
-	chs.lastPrompts, _ = cch.tgh.DeleteAllMessages(ctx, chatID, chs.lastPrompts)
 }
 
-// onClear
-func (cch *configCommandHandler) onClear(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	chs, err := cch.state(chatID)
+// updateConfig - apply a change to the chat's config, next is the step to show
+// once it's saved
+func (cch *configCommandHandler) updateConfig(s *session, next string, apply func(c *config.Config) error) (string, error) {
+	c, err := cch.configByChatID(s.Key.ChatID)
 	if err != nil {
-		return
+		return "", err
 	}
 
-	c, err := cch.configByChatID(chatID)
-	if err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		return
+	if err := apply(&c); err != nil {
+		return "", err
 	}
 
-	switch chs.activeCommand {
-	case COMMAND_SET_HASH_TAGS:
-		c.Hashtags = ""
-		if err = c.Update(&c); err != nil {
-			cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-			cch.logger.Error(
-				"an error occurred trying to clear the hashtags in the config",
-				zap.String("command", COMMAND_SET_COMMUNITY_DESC),
-				zap.Int64("chatID", chatID),
-				zap.Error(err),
-			)
-			return
-		}
-	case COMMAND_SET_CASH_TAGS:
-		c.Cashtags = ""
-		if err = c.Update(&c); err != nil {
-			cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-			cch.logger.Error(
-				"an error occurred trying to clear the cashtags in the config",
-				zap.String("command", COMMAND_SET_COMMUNITY_DESC),
-				zap.Int64("chatID", chatID),
-				zap.Error(err),
-			)
-			return
-		}
-	case COMMAND_SET_COMMUNITY_DESC:
-		c.Community = ""
-		if err = c.Update(&c); err != nil {
-			cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-			cch.logger.Error(
-				"an error occurred trying to clear the community description in the config",
-				zap.String("command", COMMAND_SET_COMMUNITY_DESC),
-				zap.Int64("chatID", chatID),
-				zap.Error(err),
-			)
-			return
-		}
-	}
-
-	chs.lastPrompts, _ = cch.tgh.DeleteLastMessage(ctx, chatID, chs.lastPrompts)
-	cch.DisplayMainMenu(ctx, b, chatID)
-}
-
-// onConfigSetTokenName
-func (cch *configCommandHandler) onConfigSetTokenName(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	chs, err := cch.state(chatID)
-	if err != nil {
-		return
-	}
-
-	message := "What is the name of your token?"
-
-	prompt, err := b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ReplyMarkup: cch.backCancelKeyboard(b),
-	})
-
-	if err != nil {
-		cch.logger.Error(
-			"failed to send \"set token name\" command prompt",
-			zap.Int64("chatID", chatID),
-			zap.Error(err),
-		)
-	}
-
-	chs.activeCommand = COMMAND_SET_TOKEN_NAME
-	chs.done = false
-	chs.lastPrompts = append(chs.lastPrompts, prompt)
-	cch.updateState(chatID, chs)
-}
-
-// onConfigSetHashtags
-func (cch *configCommandHandler) onConfigSetHashtags(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	chs, err := cch.state(chatID)
-	if err != nil {
-		return
-	}
-
-	message := `What hashtag should I use when shilling your token? 
-	
-You can set multiple hashtags but I'll only use one or two at a time.
-
-Multiple hashtags should be separated by spaces e.g.
-#MyAwesomeToken #MYTOKENTOTHEMOON #MyTokenIsTheBest
-
-Make sure your primary hashtag is set first.`
-
-	prompt, err := b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ReplyMarkup: cch.backCancelClearKeyboard(b),
-	})
-
-	if err != nil {
-		cch.logger.Error(
-			"failed to send \"set hashtags\" command prompt",
-			zap.Int64("chatID", chatID),
-			zap.Error(err),
-		)
+	if err := c.Update(&c); err != nil {
+		return "", fmt.Errorf("update config for chat %d: %w", s.Key.ChatID, err)
 	}
 
-	chs.activeCommand = COMMAND_SET_HASH_TAGS
-	chs.done = false
-	chs.lastPrompts = append(chs.lastPrompts, prompt)
-	cch.updateState(chatID, chs)
+	return next, nil
 }
 
-// onConfigSetHashtags
-func (cch *configCommandHandler) onConfigSetCashtags(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	chs, err := cch.state(chatID)
+// promptPersonas - every persona with a button to enable or disable it
+func (cch *configCommandHandler) promptPersonas(ctx context.Context, s *session, kb *keyboard) (string, error) {
+	c, err := cch.configByChatID(s.Key.ChatID)
 	if err != nil {
-		return
+		return "", err
 	}
 
-	message := `What cashtag should I use when shilling your token?
-
-e.g. $MYTOKEN
-`
-
-	prompt, err := b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ReplyMarkup: cch.backCancelClearKeyboard(b),
-	})
-
-	if err != nil {
-		cch.logger.Error(
-			"failed to send \"set cashtags\" command prompt",
-			zap.Int64("chatID", chatID),
-			zap.Error(err),
-		)
+	for _, p := range persona.All() {
+		name := p.Name
+		label := fmt.Sprintf("%s (/%s): %s", p.Title, p.Command, displayConfigToggle(s, c.PersonaEnabled(name)))
+		kb.Row().Button(label, cch.change(STEP_PERSONAS, func(c *config.Config) error {
+			c.TogglePersona(name)
+			return nil
+		}))
 	}
 
-	chs.activeCommand = COMMAND_SET_CASH_TAGS
-	chs.done = false
-	chs.lastPrompts = append(chs.lastPrompts, prompt)
-	cch.updateState(chatID, chs)
-}
-
-// onConfigSetCommunityDescription
-func (cch *configCommandHandler) onConfigSetCommunityDescription(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	chs, err := cch.state(chatID)
-	if err != nil {
-		return
-	}
-
-	message := `Describe your community (max. 500 chars).
-	
-Your description will help me create better shill responses.`
-
-	prompt, err := b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ReplyMarkup: cch.backCancelClearKeyboard(b),
-	})
-
-	if err != nil {
-		cch.logger.Error(
-			"failed to send \"set community description\" command prompt",
-			zap.Int64("chatID", chatID),
-			zap.Error(err),
-		)
-	}
-
-	chs.activeCommand = COMMAND_SET_COMMUNITY_DESC
-	chs.done = false
-	chs.lastPrompts = append(chs.lastPrompts, prompt)
-	cch.updateState(chatID, chs)
+	return s.T(i18n.ConfigPersonasPrompt), nil
 }
 
 // receiveTokenName
-func (cch *configCommandHandler) receiveTokenName(chs configHandlerState, ctx context.Context, b *bot.Bot, update *models.Update) {
-	chatID := update.Message.Chat.ID
-
-	tokenName := strings.TrimSpace(update.Message.Text)
-
-	if !cch.validateTokenName(tokenName) {
-		cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-		cch.tgh.DeleteLastMessage(ctx, chatID, chs.lastPrompts)
-		prompt, err := cch.tgh.SendMessage(ctx, b, chatID, "Invalid token name, please try again", &models.ReplyParameters{})
-		if err != nil {
-			cch.DisplayMainMenu(ctx, b, chatID)
-			return
-		}
-
-		chs.lastPrompts = append(chs.lastPrompts, prompt)
-		cch.updateState(chatID, chs)
-		return
-	}
-
-	c, err := cch.configByChatID(chatID)
-	if err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		return
-	}
-
-	c.Token = strings.ToUpper(tokenName)
-	if err = c.Update(&c); err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		cch.logger.Error(
-			"an error occurred trying to update the token name in the config",
-			zap.String("command", COMMAND_SET_TOKEN_NAME),
-			zap.Int64("chatID", chatID),
-			zap.String("token name", c.Token),
-			zap.Error(err),
-		)
-		return
-	}
-
-	cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-	chs.lastPrompts, _ = cch.tgh.DeleteLastMessage(ctx, chatID, chs.lastPrompts)
-
-	cch.DisplayMainMenu(ctx, b, chatID)
+func (cch *configCommandHandler) receiveTokenName(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+	return cch.updateConfig(s, STEP_MAIN_MENU, func(c *config.Config) error {
+		c.Token = strings.ToUpper(commandhandler.Text(message))
+		return nil
+	})
 }
 
 // validateTokenName
-func (cch *configCommandHandler) validateTokenName(tokenName string) bool {
+func (cch *configCommandHandler) validateTokenName(s *session, message *models.Message) error {
+	tokenName := commandhandler.Text(message)
 	if len(tokenName) < 1 || len(tokenName) > 32 {
-		return false
+		return errors.New(s.T(i18n.ConfigInvalidTokenName))
 	}
 
 	// Check if the string contains only allowed characters
 	isValidName := regexp.MustCompile(`^[a-zA-Z0-9 _\-]+$`).MatchString
-	return isValidName(tokenName)
-}
-
-// receiveHashTags
-func (cch *configCommandHandler) receiveHashTags(chs configHandlerState, ctx context.Context, b *bot.Bot, update *models.Update) {
-	chatID := update.Message.Chat.ID
-
-	hashtags := strings.TrimSpace(update.Message.Text)
-
-	if !cch.validateHashtags(hashtags) {
-		cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-		if len(chs.lastPrompts) > 1 {
-			chs.lastPrompts, _ = cch.tgh.DeleteLastMessage(ctx, chatID, chs.lastPrompts)
-		}
-		prompt, err := cch.tgh.SendMessage(ctx, b, chatID, "Invalid hashtags, please try again", &models.ReplyParameters{})
-		if err != nil {
-			cch.DisplayMainMenu(ctx, b, chatID)
-			return
-		}
-
-		chs.lastPrompts = append(chs.lastPrompts, prompt)
-		cch.updateState(chatID, chs)
-		return
+	if !isValidName(tokenName) {
+		return errors.New(s.T(i18n.ConfigInvalidTokenName))
 	}
 
-	c, err := cch.configByChatID(chatID)
-	if err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		return
-	}
-
-	c.Hashtags = hashtags
-	if err = c.Update(&c); err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		cch.logger.Error(
-			"an error occurred trying to update the hashtags in the config",
-			zap.String("command", COMMAND_SET_HASH_TAGS),
-			zap.Int64("chatID", chatID),
-			zap.String("hashtags", hashtags),
-			zap.Error(err),
-		)
-		return
-	}
+	return nil
+}
 
-	cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-	chs.lastPrompts, _ = cch.tgh.DeleteAllMessages(ctx, chatID, chs.lastPrompts)
-	cch.DisplayMainMenu(ctx, b, chatID)
+// receiveHashTags
+func (cch *configCommandHandler) receiveHashTags(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+	return cch.updateConfig(s, STEP_MAIN_MENU, func(c *config.Config) error {
+		c.Hashtags = commandhandler.Text(message)
+		return nil
+	})
 }
 
 // validateHashtags
-func (cch *configCommandHandler) validateHashtags(hashtags string) bool {
+func (cch *configCommandHandler) validateHashtags(s *session, message *models.Message) error {
 	// Regular expression for validating hashtags, including Unicode characters
 	re := regexp.MustCompile(`^(#[\p{L}\p{N}_]+)( #[\p{L}\p{N}_]+)*$`)
-	return re.MatchString(hashtags)
-}
-
-// receiveCashTags
-func (cch *configCommandHandler) receiveCashTags(chs configHandlerState, ctx context.Context, b *bot.Bot, update *models.Update) {
-	chatID := update.Message.Chat.ID
-
-	cashtags := strings.TrimSpace(update.Message.Text)
-
-	if !cch.validateCashtags(cashtags) {
-		cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-		if len(chs.lastPrompts) > 1 {
-			chs.lastPrompts, _ = cch.tgh.DeleteLastMessage(ctx, chatID, chs.lastPrompts)
-		}
-		prompt, err := cch.tgh.SendMessage(ctx, b, chatID, "Invalid cashtags, please try again", &models.ReplyParameters{})
-		if err != nil {
-			cch.DisplayMainMenu(ctx, b, chatID)
-			return
-		}
-
-		chs.lastPrompts = append(chs.lastPrompts, prompt)
-		cch.updateState(chatID, chs)
-		return
-	}
-
-	c, err := cch.configByChatID(chatID)
-	if err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		return
+	if !re.MatchString(commandhandler.Text(message)) {
+		return errors.New(s.T(i18n.ConfigInvalidHashtags))
 	}
 
-	c.Cashtags = cashtags
-	if err = c.Update(&c); err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		cch.logger.Error(
-			"an error occurred trying to update the cashtags in the config",
-			zap.String("command", COMMAND_SET_CASH_TAGS),
-			zap.Int64("chatID", chatID),
-			zap.String("cashtags", cashtags),
-			zap.Error(err),
-		)
-		return
-	}
+	return nil
+}
 
-	cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-	chs.lastPrompts, _ = cch.tgh.DeleteAllMessages(ctx, chatID, chs.lastPrompts)
-	cch.DisplayMainMenu(ctx, b, chatID)
+// receiveCashTags
+func (cch *configCommandHandler) receiveCashTags(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+	return cch.updateConfig(s, STEP_MAIN_MENU, func(c *config.Config) error {
+		c.Cashtags = commandhandler.Text(message)
+		return nil
+	})
 }
 
 // validateCashtags
-func (cch *configCommandHandler) validateCashtags(hashtags string) bool {
+func (cch *configCommandHandler) validateCashtags(s *session, message *models.Message) error {
 	re := regexp.MustCompile(`^(\$[A-Za-z0-9]+)( \$[A-Za-z0-9]+)*$`)
-	return re.MatchString(hashtags)
-}
-
-// receiveCommunityDescription
-func (cch *configCommandHandler) receiveCommunityDescription(chs configHandlerState, ctx context.Context, b *bot.Bot, update *models.Update) {
-	chatID := update.Message.Chat.ID
-
-	community := strings.TrimSpace(update.Message.Text)
-
-	if !cch.validateCommnunityDescription(community) {
-		cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-		if len(chs.lastPrompts) > 1 {
-			chs.lastPrompts, _ = cch.tgh.DeleteLastMessage(ctx, chatID, chs.lastPrompts)
-		}
-		prompt, err := cch.tgh.SendMessage(ctx, b, chatID, "Invalid description, please try again", &models.ReplyParameters{})
-		if err != nil {
-			cch.DisplayMainMenu(ctx, b, chatID)
-			return
-		}
-
-		chs.lastPrompts = append(chs.lastPrompts, prompt)
-		cch.updateState(chatID, chs)
-		return
+	if !re.MatchString(commandhandler.Text(message)) {
+		return errors.New(s.T(i18n.ConfigInvalidCashtags))
 	}
 
-	c, err := cch.configByChatID(chatID)
-	if err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		return
-	}
-
-	c.Community = community
-	if err = c.Update(&c); err != nil {
-		cch.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
-		cch.logger.Error(
-			"an error occurred trying to update the community description in the config",
-			zap.String("command", COMMAND_SET_COMMUNITY_DESC),
-			zap.Int64("chatID", chatID),
-			zap.String("community", community),
-			zap.Error(err),
-		)
-		return
-	}
+	return nil
+}
 
-	cch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-	chs.lastPrompts, _ = cch.tgh.DeleteAllMessages(ctx, chatID, chs.lastPrompts)
-	cch.DisplayMainMenu(ctx, b, chatID)
+// receiveCommunityDescription
+func (cch *configCommandHandler) receiveCommunityDescription(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+	return cch.updateConfig(s, STEP_MAIN_MENU, func(c *config.Config) error {
+		c.Community = commandhandler.Text(message)
+		return nil
+	})
 }
 
 // validateCommnunityDescription
-func (cch *configCommandHandler) validateCommnunityDescription(community string) bool {
-	return len(community) <= 500
+func (cch *configCommandHandler) validateCommnunityDescription(s *session, message *models.Message) error {
+	if len(commandhandler.Text(message)) > 500 {
+		return errors.New(s.T(i18n.ConfigInvalidCommunity))
+	}
+
+	return nil
 }
 
 // configByChatID
@@ -638,13 +400,3 @@ func (cch *configCommandHandler) configByChatID(chatID int64) (config.Config, er
 
 	return c, nil
 }
-
-// state
-func (cch *configCommandHandler) state(chatID int64) (configHandlerState, error) {
-	chs, ok := state[chatID]
-	if !ok {
-		return configHandlerState{}, errors.New("failed to fetch config state")
-	}
-
-	return chs, nil
-}
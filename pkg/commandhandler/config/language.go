@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/language"
+)
+
+const (
+	COMMAND_SET_PREFERRED_LANGUAGES = "setPreferredLanguages"
+	COMMAND_SET_FORCED_LANGUAGE     = "setForcedLanguage"
+)
+
+var (
+	errInvalidLanguage = errors.New("invalid language")
+)
+
+// promptLanguage - show the languages replies are written in
+func (cch *configCommandHandler) promptLanguage(ctx context.Context, s *session, kb *keyboard) (string, error) {
+	c, err := cch.configByChatID(s.Key.ChatID)
+	if err != nil {
+		return "", err
+	}
+
+	kb.Row().
+		Button(s.T(i18n.ConfigButtonPreferredLanguages), gotoStep(COMMAND_SET_PREFERRED_LANGUAGES)).
+		Button(s.T(i18n.ConfigButtonForcedLanguage), gotoStep(COMMAND_SET_FORCED_LANGUAGE))
+
+	return s.T(
+		i18n.ConfigReplyLanguage,
+		displayLanguages(s, c.Language.Preferred),
+		displayLanguages(s, []string{c.Language.Forced}),
+	), nil
+}
+
+// displayLanguages
+func displayLanguages(s *session, codes []string) string {
+	var names []string
+	for _, code := range codes {
+		if l, ok := language.ByCode(code); ok {
+			names = append(names, fmt.Sprintf("%s (%s)", l.Name, l.Code))
+		}
+	}
+
+	if len(names) == 0 {
+		return s.T(i18n.ConfigNotSet)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// preferredLanguagesStep
+func (cch *configCommandHandler) preferredLanguagesStep() step {
+	return cch.languageStep(
+		i18n.ConfigPreferredLanguagesPrompt,
+		func(ls *config.LanguageSettings, text string) error {
+			codes, valid := language.ParseCodes(text)
+			if !valid {
+				return errInvalidLanguage
+			}
+			ls.Preferred = codes
+			return nil
+		},
+		func(ls *config.LanguageSettings) {
+			ls.Preferred = nil
+		},
+	)
+}
+
+// forcedLanguageStep
+func (cch *configCommandHandler) forcedLanguageStep() step {
+	return cch.languageStep(
+		i18n.ConfigForcedLanguagePrompt,
+		func(ls *config.LanguageSettings, text string) error {
+			codes, valid := language.ParseCodes(text)
+			if !valid || len(codes) != 1 {
+				return errInvalidLanguage
+			}
+			ls.Forced = codes[0]
+			return nil
+		},
+		func(ls *config.LanguageSettings) {
+			ls.Forced = ""
+		},
+	)
+}
+
+// languageStep - a prompt for language codes, apply parses and sets them so also
+// validates the reply against a copy of the settings
+func (cch *configCommandHandler) languageStep(prompt i18n.Key, apply func(ls *config.LanguageSettings, text string) error, clear func(ls *config.LanguageSettings)) step {
+	return settingStep(
+		func(s *session) string { return s.T(prompt) },
+		func(s *session, message *models.Message) error {
+			if apply(&config.LanguageSettings{}, message.Text) != nil {
+				return errors.New(s.T(i18n.ConfigInvalidLanguage))
+			}
+
+			return nil
+		},
+		func(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+			return cch.updateConfig(s, STEP_LANGUAGE, func(c *config.Config) error {
+				return apply(&c.Language, message.Text)
+			})
+		},
+		cch.change(STEP_LANGUAGE, func(c *config.Config) error {
+			clear(&c.Language)
+			return nil
+		}),
+	)
+}
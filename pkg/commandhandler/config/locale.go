@@ -0,0 +1,37 @@
+package config
+
+import (
+	"context"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+)
+
+// displayLocale
+func displayLocale(s *session, locale string) string {
+	if !i18n.Supported(locale) {
+		return s.T(i18n.ConfigAutomatic)
+	}
+
+	return i18n.Name(locale)
+}
+
+// promptLocale - list the locales the bot's messages can be shown in
+func (cch *configCommandHandler) promptLocale(ctx context.Context, s *session, kb *keyboard) (string, error) {
+	kb.Row().Button(s.T(i18n.ConfigButtonAutomaticLocale), cch.selectLocale(""))
+
+	for _, locale := range i18n.Locales() {
+		kb.Row().Button(i18n.Name(locale), cch.selectLocale(locale))
+	}
+
+	return s.T(i18n.ConfigLocalePrompt), nil
+}
+
+// selectLocale - an empty locale uses each member's Telegram language, the main
+// menu is shown in the new locale
+func (cch *configCommandHandler) selectLocale(locale string) action {
+	return cch.change(STEP_MAIN_MENU, func(c *config.Config) error {
+		c.Locale = locale
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+)
+
+const (
+	COMMAND_SET_MANAGERS = "setManagers"
+)
+
+// displayManagers
+func displayManagers(s *session, managers []int64) string {
+	if len(managers) == 0 {
+		return s.T(i18n.ConfigNone)
+	}
+
+	ids := make([]string, len(managers))
+	for i, manager := range managers {
+		ids[i] = strconv.FormatInt(manager, 10)
+	}
+
+	return strings.Join(ids, ", ")
+}
+
+// managersStep - a reply to a member's message adds them, a list of user IDs
+// replaces the managers
+func (cch *configCommandHandler) managersStep() step {
+	return settingStep(
+		func(s *session) string {
+			return s.T(i18n.ConfigManagersPrompt)
+		},
+		func(s *session, message *models.Message) error {
+			if repliedMember(message) != nil {
+				return nil
+			}
+
+			managers, err := config.ParseUserIDs(message.Text)
+			if err != nil || len(managers) > config.MaxManagers {
+				return errors.New(s.T(i18n.ConfigInvalidManagers))
+			}
+
+			return nil
+		},
+		func(ctx context.Context, b *bot.Bot, s *session, message *models.Message) (string, error) {
+			return cch.updateConfig(s, STEP_MAIN_MENU, func(c *config.Config) error {
+				if member := repliedMember(message); member != nil {
+					if len(c.Managers) < config.MaxManagers {
+						c.AddManager(member.ID)
+					}
+					return nil
+				}
+
+				managers, err := config.ParseUserIDs(message.Text)
+				if err != nil {
+					return err
+				}
+
+				c.Managers = nil
+				for _, manager := range managers {
+					c.AddManager(manager)
+				}
+				return nil
+			})
+		},
+		cch.change(STEP_MAIN_MENU, func(c *config.Config) error {
+			c.Managers = nil
+			return nil
+		}),
+	)
+}
+
+// repliedMember - the member whose message was replied to, nil for replies to the bot
+func repliedMember(message *models.Message) *models.User {
+	reply := message.ReplyToMessage
+	if reply == nil || reply.From == nil || reply.From.IsBot {
+		return nil
+	}
+
+	return reply.From
+}
@@ -0,0 +1,502 @@
+package commandhandler
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/tghelper"
+	"go.uber.org/zap"
+)
+
+const (
+	// ButtonCallbackPrefix - conversation buttons carry the conversation, step and
+	// button pressed with the session's chat and user, see ButtonCallbackData. The
+	// bot handles them all so they survive restarts and work on every replica.
+	ButtonCallbackPrefix = "cv:"
+
+	maxCallbackDataLength = 64
+)
+
+// ButtonCallback - a pressed conversation button
+type ButtonCallback struct {
+	Conversation string
+	Step         string
+	Button       int
+	SessionKey   SessionKey
+}
+
+// Session - one user's progress through a conversation, persisted in the state
+// store between messages. Data holds the conversation's own values.
+type Session[S any] struct {
+	Key     SessionKey        `json:"key"`
+	User    models.User       `json:"user"`
+	Locale  string            `json:"locale"`
+	Steps   StepHistory       `json:"steps"`
+	Prompts []*models.Message `json:"prompts"`
+	Done    bool              `json:"done"`
+	Data    S                 `json:"data"`
+}
+
+// NewSession
+func NewSession[S any](sk SessionKey, user models.User, locale string) *Session[S] {
+	return &Session[S]{
+		Key:    sk,
+		User:   user,
+		Locale: locale,
+	}
+}
+
+// InProgress - started and neither finished nor cancelled
+func (s *Session[S]) InProgress() bool {
+	return !s.Done && s.Steps.Current() != ""
+}
+
+// T - the message for key in the session's locale
+func (s *Session[S]) T(key i18n.Key, args ...any) string {
+	return i18n.T(s.Locale, key, args...)
+}
+
+// Conversation - the steps of a multi-step command and its users' sessions. It
+// provides the Cancel, Reset and Done of a CommandHandler, the handler starts
+// sessions and passes replies on with Receive.
+type Conversation[S any] struct {
+	name    string
+	steps   map[string]Step[S]
+	timeout time.Duration
+	logger  *zap.Logger
+	store   storage.StateStore
+	mongo   *storage.Mongo
+	locks   SessionLocks
+}
+
+// NewConversation - name prefixes the state store keys of the conversation's
+// sessions and identifies its buttons
+func NewConversation[S any](name string, logger *zap.Logger) *Conversation[S] {
+	return &Conversation[S]{
+		name:   name,
+		steps:  map[string]Step[S]{},
+		logger: logger,
+		store:  storage.SharedStateStore(),
+	}
+}
+
+// Step - add a step, its name is what actions return to show it
+func (cv *Conversation[S]) Step(name string, step Step[S]) *Conversation[S] {
+	cv.steps[name] = step
+	return cv
+}
+
+// WithTimeout - idle time allowed before the bot times a session out, overridden
+// by sessions.timeout.<command>
+func (cv *Conversation[S]) WithTimeout(timeout time.Duration) *Conversation[S] {
+	cv.timeout = timeout
+	return cv
+}
+
+// WithMongo - the database chats' configs are read from, sessions are kept for
+// the chat's own state ttl. Without one state.ttl is used
+func (cv *Conversation[S]) WithMongo(mongo *storage.Mongo) *Conversation[S] {
+	cv.mongo = mongo
+	return cv
+}
+
+// Timeout
+func (cv *Conversation[S]) Timeout() time.Duration {
+	return cv.timeout
+}
+
+// TGHelper - a helper sending messages in the session's locale
+func (cv *Conversation[S]) TGHelper(b *bot.Bot, s *Session[S]) tghelper.TGHelper {
+	return tghelper.NewTGHelper(b, cv.logger).WithLocale(s.Locale)
+}
+
+// Start - begin a new session, replacing any the user already has, and show the
+// step action returns. Actions run with the session locked so must not call
+// Press, Receive or Start for it themselves.
+func (cv *Conversation[S]) Start(ctx context.Context, b *bot.Bot, s *Session[S], action Action[S]) {
+	defer cv.locks.Lock(s.Key)()
+
+	if previous, ok := cv.session(s.Key); ok {
+		cv.deletePrompts(ctx, b, previous)
+	}
+
+	cv.run(ctx, b, s, action)
+}
+
+// Press - run the action of a pressed button. The step's buttons are rebuilt from
+// the stored session, so they work on any replica and after a restart. False when
+// the button isn't one of the conversation's or its session has moved on.
+func (cv *Conversation[S]) Press(ctx context.Context, b *bot.Bot, bc ButtonCallback) bool {
+	if bc.Conversation != cv.name {
+		return false
+	}
+
+	defer cv.locks.Lock(bc.SessionKey)()
+
+	s, ok := cv.session(bc.SessionKey)
+	if !ok || !s.InProgress() || s.Steps.Current() != bc.Step {
+		return false
+	}
+
+	_, kb, err := cv.prompt(ctx, s, bc.Step)
+	if err != nil {
+		cv.transition(ctx, b, s, bc.Step, err)
+		return true
+	}
+
+	if bc.Button < 0 || bc.Button >= len(kb.actions) {
+		return false
+	}
+
+	cv.run(ctx, b, s, kb.actions[bc.Button])
+	return true
+}
+
+// Receive - pass a message to the step the sender's session is on, false when no
+// step is waiting for a reply. The message is deleted once the step accepts it,
+// rejected replies are kept so the user can correct them.
+func (cv *Conversation[S]) Receive(ctx context.Context, b *bot.Bot, update *models.Update) bool {
+	message := update.Message
+	sk := SessionKeyFromMessage(message)
+	defer cv.locks.Lock(sk)()
+
+	s, ok := cv.session(sk)
+	if !ok || !s.InProgress() {
+		return false
+	}
+
+	step, ok := cv.steps[s.Steps.Current()]
+	if !ok || step.Receive == nil {
+		return false
+	}
+
+	if step.Validate != nil {
+		if err := step.Validate(s, message); err != nil {
+			cv.show(ctx, b, s, s.Steps.Current(), err.Error())
+			return true
+		}
+	}
+
+	tgh := cv.TGHelper(b, s)
+	tgh.DeleteMessage(ctx, s.Key.ChatID, message.ID)
+
+	next, err := step.Receive(ctx, b, s, message)
+	cv.transition(ctx, b, s, next, err)
+	return true
+}
+
+// Cancelled
+func (cv *Conversation[S]) Cancelled(sk SessionKey) bool {
+	return false
+}
+
+// Cancel - remove the session's prompts and finish it
+func (cv *Conversation[S]) Cancel(ctx context.Context, b *bot.Bot, sk SessionKey) {
+	defer cv.locks.Lock(sk)()
+
+	if s, ok := cv.session(sk); ok {
+		cv.finish(ctx, b, s)
+	}
+}
+
+// Reset - remove the session's prompts and forget it
+func (cv *Conversation[S]) Reset(ctx context.Context, b *bot.Bot, sk SessionKey) {
+	defer cv.locks.Lock(sk)()
+
+	s, ok := cv.session(sk)
+	if !ok {
+		return
+	}
+
+	cv.deletePrompts(ctx, b, s)
+
+	if err := cv.store.Delete(cv.stateKey(sk)); err != nil {
+		cv.logger.Error(
+			"an error occurred trying to clear conversation state",
+			zap.String("conversation", cv.name),
+			zap.String("session", sk.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// Done
+func (cv *Conversation[S]) Done(sk SessionKey) bool {
+	defer cv.locks.Lock(sk)()
+
+	s, ok := cv.session(sk)
+	if !ok {
+		return false
+	}
+
+	return s.Done
+}
+
+// run
+func (cv *Conversation[S]) run(ctx context.Context, b *bot.Bot, s *Session[S], action Action[S]) {
+	next, err := action(ctx, b, s)
+	cv.transition(ctx, b, s, next, err)
+}
+
+// transition - show the next step or finish, a failed step is reported and shown again
+func (cv *Conversation[S]) transition(ctx context.Context, b *bot.Bot, s *Session[S], next string, err error) {
+	if err != nil {
+		cv.logger.Error(
+			"an error occurred in a conversation step",
+			zap.String("conversation", cv.name),
+			zap.String("session", s.Key.String()),
+			zap.String("step", s.Steps.Current()),
+			zap.Error(err),
+		)
+
+		tgh := cv.TGHelper(b, s)
+		tgh.SendErrorTryAgainMessage(ctx, b, s.Key.ChatID)
+		next = s.Steps.Current()
+	}
+
+	if next == STEP_END {
+		cv.finish(ctx, b, s)
+		return
+	}
+
+	cv.show(ctx, b, s, next, "")
+}
+
+// show - replace the session's prompts with the step's, notice is shown above it
+// e.g. why a reply was rejected
+func (cv *Conversation[S]) show(ctx context.Context, b *bot.Bot, s *Session[S], name string, notice string) {
+	if _, ok := cv.steps[name]; !ok {
+		cv.logger.Error(
+			"an unknown conversation step was requested",
+			zap.String("conversation", cv.name),
+			zap.String("step", name),
+		)
+		cv.finish(ctx, b, s)
+		return
+	}
+
+	s.Steps.Push(name)
+	s.Done = false
+
+	text, kb, err := cv.prompt(ctx, s, name)
+	if err != nil {
+		cv.logger.Error(
+			"an error occurred trying to prompt a conversation step",
+			zap.String("conversation", cv.name),
+			zap.String("session", s.Key.String()),
+			zap.String("step", name),
+			zap.Error(err),
+		)
+
+		tgh := cv.TGHelper(b, s)
+		tgh.SendErrorTryAgainMessage(ctx, b, s.Key.ChatID)
+		cv.finish(ctx, b, s)
+		return
+	}
+
+	if notice != "" {
+		text = html.EscapeString(notice) + "\n\n" + text
+	}
+
+	cv.deletePrompts(ctx, b, s)
+
+	prompt, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      s.Key.ChatID,
+		Text:        text,
+		ParseMode:   models.ParseModeHTML,
+		ReplyMarkup: kb.kb.Markup(),
+	})
+
+	if err != nil {
+		cv.logger.Error(
+			"failed to send conversation prompt",
+			zap.String("conversation", cv.name),
+			zap.String("session", s.Key.String()),
+			zap.String("step", name),
+			zap.Error(err),
+		)
+	} else {
+		s.Prompts = append(s.Prompts, prompt)
+	}
+
+	cv.save(s)
+}
+
+// prompt - the step's message and buttons for the session, with Back and Cancel
+func (cv *Conversation[S]) prompt(ctx context.Context, s *Session[S], name string) (string, *Keyboard[S], error) {
+	step := cv.steps[name]
+	kb := &Keyboard[S]{
+		cv:   cv,
+		sk:   s.Key,
+		step: name,
+		kb:   tghelper.NewKeyboard(),
+	}
+
+	text, err := step.Prompt(ctx, s, kb)
+	if err != nil {
+		return "", nil, err
+	}
+
+	kb.kb.Row()
+	if !step.NoBack && len(s.Steps) > 1 {
+		kb.Button(s.T(i18n.ButtonBack), cv.back)
+	}
+	if !step.NoCancel {
+		kb.kb.Cancel(s.T(i18n.ButtonCancel), s.Key.ChatID, s.Key.UserID)
+	}
+
+	return text, kb, nil
+}
+
+// back - leave the current step for the one before it
+func (cv *Conversation[S]) back(ctx context.Context, b *bot.Bot, s *Session[S]) (string, error) {
+	step, ok := s.Steps.Back()
+	if !ok {
+		return s.Steps.Current(), nil
+	}
+
+	return step, nil
+}
+
+// finish - remove the session's prompts, the session is kept so the bot sees it's done
+func (cv *Conversation[S]) finish(ctx context.Context, b *bot.Bot, s *Session[S]) {
+	cv.deletePrompts(ctx, b, s)
+	s.Done = true
+	cv.save(s)
+}
+
+// deletePrompts
+func (cv *Conversation[S]) deletePrompts(ctx context.Context, b *bot.Bot, s *Session[S]) {
+	tgh := cv.TGHelper(b, s)
+	s.Prompts, _ = tgh.DeleteAllMessages(ctx, s.Key.ChatID, s.Prompts)
+}
+
+// session
+func (cv *Conversation[S]) session(sk SessionKey) (*Session[S], bool) {
+	var s Session[S]
+	ok, err := cv.store.Load(cv.stateKey(sk), &s)
+	if err != nil {
+		cv.logger.Error(
+			"an error occurred trying to load conversation state",
+			zap.String("conversation", cv.name),
+			zap.String("session", sk.String()),
+			zap.Error(err),
+		)
+		return nil, false
+	}
+
+	return &s, ok
+}
+
+// save
+func (cv *Conversation[S]) save(s *Session[S]) {
+	if err := cv.store.Save(cv.stateKey(s.Key), s, config.StateTTL(cv.mongo, s.Key.ChatID)); err != nil {
+		cv.logger.Error(
+			"an error occurred trying to save conversation state",
+			zap.String("conversation", cv.name),
+			zap.String("session", s.Key.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// stateKey
+func (cv *Conversation[S]) stateKey(sk SessionKey) string {
+	return fmt.Sprintf("%s:%s", cv.name, sk)
+}
+
+// Keyboard - the buttons of a step's prompt, only the session's user can press them.
+// A button is identified by its position so a step must add the same buttons for
+// the same session state.
+type Keyboard[S any] struct {
+	cv      *Conversation[S]
+	sk      SessionKey
+	step    string
+	kb      *tghelper.Keyboard
+	actions []Action[S]
+}
+
+// Row
+func (kb *Keyboard[S]) Row() *Keyboard[S] {
+	kb.kb.Row()
+	return kb
+}
+
+// Button - action runs against the session when the button is pressed
+func (kb *Keyboard[S]) Button(text string, action Action[S]) *Keyboard[S] {
+	data := ButtonCallbackData(kb.cv.name, kb.step, len(kb.actions), kb.sk)
+	if len(data) > maxCallbackDataLength {
+		kb.cv.logger.Error(
+			"button callback data is too long for telegram",
+			zap.String("conversation", kb.cv.name),
+			zap.String("step", kb.step),
+			zap.String("data", data),
+		)
+	}
+
+	kb.kb.Button(text, data)
+	kb.actions = append(kb.actions, action)
+
+	return kb
+}
+
+// ButtonCallbackData - e.g. "cv:config:mainMenu:3:<chatID>:<userID>" for the fourth
+// button of the config main menu
+func ButtonCallbackData(conversation string, step string, button int, sk SessionKey) string {
+	return fmt.Sprintf("%s%s:%s:%d:%d:%d", ButtonCallbackPrefix, conversation, step, button, sk.ChatID, sk.UserID)
+}
+
+// ParseButtonCallbackData
+func ParseButtonCallbackData(data string) (ButtonCallback, bool) {
+	if !strings.HasPrefix(data, ButtonCallbackPrefix) {
+		return ButtonCallback{}, false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(data, ButtonCallbackPrefix), ":")
+	if len(parts) != 5 {
+		return ButtonCallback{}, false
+	}
+
+	button, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return ButtonCallback{}, false
+	}
+
+	var sk SessionKey
+	if _, err := fmt.Sscanf(parts[3]+":"+parts[4], "%d:%d", &sk.ChatID, &sk.UserID); err != nil {
+		return ButtonCallback{}, false
+	}
+
+	return ButtonCallback{
+		Conversation: parts[0],
+		Step:         parts[1],
+		Button:       button,
+		SessionKey:   sk,
+	}, true
+}
+
+// Text - a message's text without surrounding whitespace
+func Text(message *models.Message) string {
+	return strings.TrimSpace(message.Text)
+}
+
+// CommandName - the command a message starts with, without its slash or @bot
+// suffix, empty when the message isn't a command
+func CommandName(message *models.Message) string {
+	fields := strings.Fields(Text(message))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	return name
+}
@@ -0,0 +1,48 @@
+package commandhandler
+
+import (
+	"sync"
+)
+
+// SessionLocks - a mutex per session, so a session waiting on Telegram or the
+// database doesn't hold up every other session. The zero value is ready to use.
+type SessionLocks struct {
+	locks map[SessionKey]*sessionLock
+	mutex sync.Mutex
+}
+
+type sessionLock struct {
+	sync.Mutex
+	users int
+}
+
+// Lock - lock the session, the returned func unlocks it. A session's mutex is
+// forgotten once nobody holds or waits for it.
+func (sl *SessionLocks) Lock(sk SessionKey) func() {
+	sl.mutex.Lock()
+	if sl.locks == nil {
+		sl.locks = map[SessionKey]*sessionLock{}
+	}
+
+	lock, ok := sl.locks[sk]
+	if !ok {
+		lock = &sessionLock{}
+		sl.locks[sk] = lock
+	}
+	lock.users++
+	sl.mutex.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		sl.mutex.Lock()
+		defer sl.mutex.Unlock()
+
+		lock.users--
+		if lock.users == 0 {
+			delete(sl.locks, sk)
+		}
+	}
+}
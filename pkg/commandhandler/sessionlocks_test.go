@@ -0,0 +1,47 @@
+package commandhandler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionLocks(t *testing.T) {
+	var sl SessionLocks
+	a := NewSessionKey(-100, 1)
+	b := NewSessionKey(-100, 2)
+
+	unlockA := sl.Lock(a)
+
+	locked := make(chan struct{})
+	go func() {
+		sl.Lock(b)()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("another session had to wait for the locked session")
+	}
+
+	lockedAgain := make(chan struct{})
+	go func() {
+		sl.Lock(a)()
+		close(lockedAgain)
+	}()
+
+	select {
+	case <-lockedAgain:
+		t.Fatal("the session was locked twice at once")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlockA()
+	<-lockedAgain
+
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	if len(sl.locks) != 0 {
+		t.Errorf("%d unused session locks were kept", len(sl.locks))
+	}
+}
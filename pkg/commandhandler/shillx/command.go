@@ -4,12 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"log"
 	"net/url"
 	"os"
 	"regexp"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -17,42 +18,54 @@ import (
 	"github.com/spf13/viper"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/persona"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/tghelper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/tweetfetcher"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 const (
-	REPLY_TYPE_SHILL = "shill"
-	REPLY_TYPE_TROLL = "troll"
+	REPLY_TYPE_SHILL = persona.PERSONA_SHILL
+	REPLY_TYPE_TROLL = persona.PERSONA_TROLL
+
+	STEP_TWEET_LINK = "tweetLink"
+	STEP_TWEET_TEXT = "tweetText"
+
+	sessionTimeout = 5 * time.Minute
 )
 
 var (
-	state      = make(map[int64]ShillHandlerState)
-	stateMutex = &sync.RWMutex{}
+	commandArgsRegex = regexp.MustCompile(`^/\S+\s*(\S*)\s*([\s\S]*)$`)
+	tweetURLRegex    = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?(?:twitter|x)\.com/[A-Za-z0-9_]+/status/\d+(?:\?\S*)?`)
 )
 
-type ShillHandlerState struct {
-	ChatID     int64
-	inProgress bool
-	done       bool
-	tweetLink  string
-	tweetText  string
-	ReplyType  string
-	lastPrompt *models.Message
-	user       models.User
+// ShillData - the values a shill session collects
+type ShillData struct {
+	TweetLink string `json:"tweetLink"`
+	TweetText string `json:"tweetText"`
+	ReplyType string `json:"replyType"`
 }
 
+// ShillCommandHandler - asks for the tweet link, and its text when it can't be
+// fetched, then posts the link to the generated reply
 type ShillCommandHandler struct {
-	commandhandler.Command
-	tgh    tghelper.TGHelper
-	logger *zap.Logger
-	mongo  *storage.Mongo
+	*commandhandler.Conversation[ShillData]
+	logger    *zap.Logger
+	mongo     *storage.Mongo
+	fetcher   tweetfetcher.TweetFetcher
+	replyType string
 }
 
 // NewShillCommandHandler
 func NewShillCommandHandler() commandhandler.CommandHandler {
+	return NewPersonaCommandHandler(REPLY_TYPE_SHILL)
+}
+
+// NewPersonaCommandHandler - the shill flow replying in the voice of the named persona
+func NewPersonaCommandHandler(replyType string) *ShillCommandHandler {
 	atom := zap.NewAtomicLevel()
 	encoderCfg := zap.NewProductionEncoderConfig()
 	logger := zap.New(zapcore.NewCore(
@@ -62,238 +75,242 @@ func NewShillCommandHandler() commandhandler.CommandHandler {
 	))
 	defer logger.Sync()
 
-	return &ShillCommandHandler{
-		logger: logger,
-		mongo:  storage.NewMongo(),
+	fetcher, err := tweetfetcher.NewTweetFetcher()
+	if err != nil {
+		logger.Error(
+			"an error occurred trying to create the tweet fetcher, tweet text will be requested manually",
+			zap.Error(err),
+		)
+	}
+
+	sch := &ShillCommandHandler{
+		logger:    logger,
+		mongo:     storage.NewMongo(),
+		fetcher:   fetcher,
+		replyType: replyType,
 	}
+
+	sch.Conversation = commandhandler.NewConversation[ShillData](replyType, logger).
+		WithTimeout(sessionTimeout).
+		WithMongo(sch.mongo).
+		Step(STEP_TWEET_LINK, commandhandler.Step[ShillData]{
+			Prompt:   sch.promptTweetLink,
+			Validate: sch.validateTweetLink,
+			Receive:  sch.receiveTweetLink,
+		}).
+		Step(STEP_TWEET_TEXT, commandhandler.Step[ShillData]{
+			Prompt:  sch.promptTweetText,
+			Receive: sch.receiveTweetText,
+		})
+
+	return sch
 }
 
-// Handle
+// Handle - the persona's command starts a session, sending it again part way
+// through starts over. Other messages only answer the open prompt.
 func (sch *ShillCommandHandler) Handle(ctx context.Context, b *bot.Bot, update *models.Update) {
-	stateMutex.Lock()
-	defer stateMutex.Unlock()
+	p, _ := persona.ByName(sch.replyType)
+	if commandhandler.CommandName(update.Message) != p.Command {
+		sch.Receive(ctx, b, update)
+		return
+	}
 
-	chatID := update.Message.Chat.ID
+	tweetLink, tweetText := parseCommandArgs(update.Message.Text)
+	sch.Start(ctx, b, sch.newSession(commandhandler.SessionKeyFromMessage(update.Message), *update.Message.From), sch.start(update.Message, tweetLink, tweetText))
+}
 
-	shs, ok := state[chatID]
-	if !ok {
-		shs = ShillHandlerState{
-			ChatID:    chatID,
-			ReplyType: REPLY_TYPE_SHILL,
-		}
-	}
+// StartWithTweetLink - start a new session with the tweet link already provided,
+// e.g. from a link spotted in a group message
+func (sch *ShillCommandHandler) StartWithTweetLink(ctx context.Context, b *bot.Bot, sk commandhandler.SessionKey, user models.User, tweetLink string) {
+	sch.Start(ctx, b, sch.newSession(sk, user), sch.start(nil, tweetLink, ""))
+}
 
-	sch.tgh = tghelper.NewTGHelper(b, sch.logger)
+// newSession - replies in the member's language until the chat's config is loaded
+func (sch *ShillCommandHandler) newSession(sk commandhandler.SessionKey, user models.User) *commandhandler.Session[ShillData] {
+	s := commandhandler.NewSession[ShillData](sk, user, i18n.Resolve("", user.LanguageCode))
+	s.Data.ReplyType = sch.replyType
 
-	c, err := sch.configByChatID(chatID, ctx, b)
-	if err != nil {
-		return
-	}
+	return s
+}
 
-	// canShill := !shs.inProgress || shs.done
+// start - /shillx <tweet-url> [tweet text…] skips the prompts answered inline, the
+// command is deleted once its tweet link is accepted
+func (sch *ShillCommandHandler) start(command *models.Message, tweetLink string, tweetText string) commandhandler.Action[ShillData] {
+	return func(ctx context.Context, b *bot.Bot, s *commandhandler.Session[ShillData]) (string, error) {
+		c, ok, err := sch.configByChatID(ctx, b, s)
+		if err != nil || !ok {
+			return commandhandler.STEP_END, err
+		}
+		s.Locale = i18n.Resolve(c.Locale, s.User.LanguageCode)
 
-	// if !canShill {
-	// 	message := fmt.Sprintf("%s in progress, click cancel or /cancel to start again", shs.ReplyType)
-	// 	_, err := sch.tgh.SendMessage(ctx, b, chatID, message, &models.ReplyParameters{})
-	// 	if err != nil {
-	// 		sch.logger.Warn(
-	// 			"an issue occurred while to send already shilling message",
-	// 			zap.Error(err),
-	// 		)
-	// 	}
+		if tweetLink == "" {
+			return STEP_TWEET_LINK, nil
+		}
 
-	// 	sch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-	// 	return
-	// }
+		tgh := sch.TGHelper(b, s)
+		if !sch.isTweetURL(tweetLink) {
+			tgh.SendMessage(ctx, b, s.Key.ChatID, tgh.T(i18n.ShillInvalidTweetURL), &models.ReplyParameters{})
+			return commandhandler.STEP_END, nil
+		}
 
-	if !shs.inProgress {
-		sch.requestTweetLink(shs, ctx, b, update)
-		return
-	}
+		if err := sch.setTweetLink(s, tweetLink); err != nil {
+			return commandhandler.STEP_END, err
+		}
 
-	// if shs.user.ID != update.Message.From.ID {
-	// 	message := fmt.Sprintf("%s in progress, wait your turn", shs.ReplyType)
-	// 	_, err := sch.tgh.SendMessage(ctx, b, chatID, message, &models.ReplyParameters{})
-	// 	if err != nil {
-	// 		sch.logger.Warn(
-	// 			"an issue occurred while to send already shilling message",
-	// 			zap.Error(err),
-	// 		)
-	// 	}
-	// 	sch.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
-	// 	return
-	// }
-
-	if shs.tweetLink == "" {
-		if err := sch.receiveTweetLink(shs, ctx, b, update); err != nil {
-			return
+		if command != nil {
+			tgh.DeleteMessage(ctx, s.Key.ChatID, command.ID)
 		}
 
-		sch.requestTweetText(state[chatID], ctx, b)
-		return
-	}
+		if tweetText == "" {
+			return sch.fillTweetText(ctx, b, s)
+		}
 
-	sch.generateShill(shs, ctx, b, update, c)
+		s.Data.TweetText = tweetText
+		return sch.generateShill(ctx, b, s)
+	}
 }
 
-// Reset
-func (sch *ShillCommandHandler) Reset(ctx context.Context, b *bot.Bot, chatID int64) {}
+// promptTweetLink - going back shows the tweet link given before
+func (sch *ShillCommandHandler) promptTweetLink(ctx context.Context, s *commandhandler.Session[ShillData], kb *commandhandler.Keyboard[ShillData]) (string, error) {
+	if s.Data.TweetLink != "" {
+		return s.T(i18n.ShillPreviousTweetURL, html.EscapeString(s.Data.TweetLink)), nil
+	}
 
-// Cancel
-func (sch *ShillCommandHandler) Cancelled(chatID int64) bool {
-	return false
+	return s.T(i18n.ShillRequestTweetLink), nil
 }
 
-// Cancel
-func (sch *ShillCommandHandler) Cancel(chatID int64) {
-	shs, ok := state[chatID]
-	if !ok {
-		return
+// validateTweetLink
+func (sch *ShillCommandHandler) validateTweetLink(s *commandhandler.Session[ShillData], message *models.Message) error {
+	if !sch.isTweetURL(message.Text) {
+		return errors.New(s.T(i18n.ShillRetryTweetURL))
 	}
 
-	shs.inProgress = false
-	shs.done = true
-	sch.UpdateState(shs.ChatID, shs)
+	return nil
 }
 
-// Done
-func (sch *ShillCommandHandler) Done(chatID int64) bool {
-	shs, ok := state[chatID]
-	if !ok {
-		return false
+// receiveTweetLink
+func (sch *ShillCommandHandler) receiveTweetLink(ctx context.Context, b *bot.Bot, s *commandhandler.Session[ShillData], message *models.Message) (string, error) {
+	if err := sch.setTweetLink(s, message.Text); err != nil {
+		return commandhandler.STEP_END, err
 	}
 
-	return shs.done
+	return sch.fillTweetText(ctx, b, s)
 }
 
-// requestTweetLink
-func (sch *ShillCommandHandler) requestTweetLink(shs ShillHandlerState, ctx context.Context, b *bot.Bot, update *models.Update) error {
-	shs.inProgress = true
-	shs.done = false
-	shs.user = *update.Message.From
-	sch.UpdateState(shs.ChatID, shs)
-	prompt, err := sch.tgh.SendMessageWithCancel(ctx, b, shs.ChatID, "Please provide the tweet link")
+// setTweetLink - store the tweet url without its query string, a new link needs
+// its text again
+func (sch *ShillCommandHandler) setTweetLink(s *commandhandler.Session[ShillData], rawURL string) error {
+	parsedUrl, err := url.Parse(strings.TrimSpace(rawURL))
 	if err != nil {
-		sch.SendMessageAndFinish(shs, ctx, b, shs.ChatID, "sorry an error occurred, please try again 1")
-		sch.logger.Error(
-			"an error occurred trying to sendMessageWithCancel",
-			zap.Error(err),
-		)
 		return err
 	}
-	shs.lastPrompt = prompt
-	sch.UpdateState(shs.ChatID, shs)
+	parsedUrl.RawQuery = ""
+
+	s.Data.TweetLink = parsedUrl.String()
+	s.Data.TweetText = ""
+	s.Steps.Push(STEP_TWEET_LINK)
 
 	return nil
 }
 
-func (sch *ShillCommandHandler) SendMessageAndFinish(shs ShillHandlerState, ctx context.Context, b *bot.Bot, chatID int64, message string) {
-	sch.tgh.SendMessage(ctx, b, chatID, message, &models.ReplyParameters{})
-	shs.inProgress = false
-	shs.done = true
-	sch.UpdateState(shs.ChatID, shs)
-}
+// fillTweetText - look the tweet text up and go straight to generating the shill,
+// falling back to asking for the text when the lookup fails
+func (sch *ShillCommandHandler) fillTweetText(ctx context.Context, b *bot.Bot, s *commandhandler.Session[ShillData]) (string, error) {
+	tweetText, ok := sch.fetchTweetText(ctx, s.Data.TweetLink)
+	if !ok {
+		return STEP_TWEET_TEXT, nil
+	}
 
-// receiveTweetLink
-func (sch *ShillCommandHandler) receiveTweetLink(shs ShillHandlerState, ctx context.Context, b *bot.Bot, update *models.Update) error {
+	s.Data.TweetText = tweetText
+	return sch.generateShill(ctx, b, s)
+}
 
-	if !sch.isTweetURL(update.Message.Text) {
-		errorMessage := "not a valid tweet url, please start again"
-		sch.SendMessageAndFinish(shs, ctx, b, shs.ChatID, errorMessage)
-		return errors.New(errorMessage)
+// fetchTweetText
+func (sch *ShillCommandHandler) fetchTweetText(ctx context.Context, tweetLink string) (string, bool) {
+	if sch.fetcher == nil {
+		return "", false
 	}
 
-	tweetUrl := update.Message.Text
-	parsedUrl, err := url.Parse(tweetUrl)
+	tweet, err := sch.fetcher.FetchTweet(ctx, tweetLink)
 	if err != nil {
-		errorMessage := "sorry an error occurred, please try again 3"
-		sch.SendMessageAndFinish(shs, ctx, b, shs.ChatID, errorMessage)
-		return errors.New(errorMessage)
+		if !errors.Is(err, tweetfetcher.ErrDisabled) {
+			sch.logger.Warn(
+				"unable to fetch tweet text, requesting it instead",
+				zap.String("tweetLink", tweetLink),
+				zap.Error(err),
+			)
+		}
+		return "", false
 	}
-	parsedUrl.RawQuery = ""
-	tweetUrl = parsedUrl.String()
-
-	shs.tweetLink = tweetUrl
-	sch.UpdateState(shs.ChatID, shs)
 
-	sch.tgh.DeleteMessage(ctx, shs.ChatID, update.Message.ID)
-	sch.tgh.DeleteMessage(ctx, shs.ChatID, shs.lastPrompt.ID)
-	return nil
+	return tweet.Text, true
 }
 
-// requestTweetText
-func (sch *ShillCommandHandler) requestTweetText(shs ShillHandlerState, ctx context.Context, b *bot.Bot) error {
-	prompt, err := sch.tgh.SendMessageWithCancel(ctx, b, shs.ChatID, "Please provide the original tweet text")
-	if err != nil {
-		sch.SendMessageAndFinish(shs, ctx, b, shs.ChatID, "sorry an error occurred, please try again 4")
-		sch.logger.Error(
-			"an error occurred trying to SendMessageWithCancel",
-			zap.Error(err),
-		)
-		return err
-	}
-	shs.lastPrompt = prompt
-	sch.UpdateState(shs.ChatID, shs)
-
-	return nil
+// promptTweetText
+func (sch *ShillCommandHandler) promptTweetText(ctx context.Context, s *commandhandler.Session[ShillData], kb *commandhandler.Keyboard[ShillData]) (string, error) {
+	return s.T(i18n.ShillRequestTweetText), nil
 }
 
-// generateShill
-func (sch *ShillCommandHandler) generateShill(shs ShillHandlerState, ctx context.Context, b *bot.Bot, update *models.Update, c config.Config) {
-	shs.tweetText = update.Message.Text
-	sch.UpdateState(shs.ChatID, shs)
+// receiveTweetText
+func (sch *ShillCommandHandler) receiveTweetText(ctx context.Context, b *bot.Bot, s *commandhandler.Session[ShillData], message *models.Message) (string, error) {
+	s.Data.TweetText = message.Text
+	return sch.generateShill(ctx, b, s)
+}
 
-	sch.tgh.DeleteMessage(ctx, shs.ChatID, update.Message.ID)
-	sch.tgh.DeleteMessage(ctx, shs.ChatID, shs.lastPrompt.ID)
+// generateShill - post the link to the reply page, which ends the session
+func (sch *ShillCommandHandler) generateShill(ctx context.Context, b *bot.Bot, s *commandhandler.Session[ShillData]) (string, error) {
+	tgh := sch.TGHelper(b, s)
 
-	link, err := sch.generateShillLink(shs.ChatID)
+	link, err := sch.generateShillLink(s)
 	if err != nil {
-		sch.SendMessageAndFinish(shs, ctx, b, shs.ChatID, "sorry an error occurred, please try again 5")
+		tgh.SendMessage(ctx, b, s.Key.ChatID, tgh.T(i18n.ShillError, 5), &models.ReplyParameters{})
 		sch.logger.Error(
 			"an error occurred trying to generateShillLink",
 			zap.Error(err),
 		)
-		return
+		return commandhandler.STEP_END, nil
 	}
 
-	buttonLabel := "SHILL NOW!!"
-	adjective := "shilling"
-	action := "SHILL"
-	if shs.ReplyType == REPLY_TYPE_TROLL {
-		buttonLabel = "TROLL NOW!!"
-		adjective = "trolling"
-		action = "TROLL"
+	rp, ok := persona.ByName(s.Data.ReplyType)
+	if !ok {
+		rp, _ = persona.ByName(REPLY_TYPE_SHILL)
 	}
+	buttonLabel := rp.ButtonLabel
+	adjective := rp.Adjective
+	action := rp.Action
 
 	advertiseHere := "\n\nPowered by $TROLLANA - https://t.me/TROLLANAOfficial"
 	advertiseHere = ""
 
-	message := `%v
-
-Let's go %v baby!!
-	
-Just click on the %v NOW button to generate your own, AI %v reply.%v
-	`
-	message = fmt.Sprintf(message, shs.tweetLink, adjective, action, action, advertiseHere)
+	message := tgh.T(i18n.ShillReady, s.Data.TweetLink, adjective, action, action, advertiseHere)
 	message = tghelper.EscapeChars(message)
 
 	dialogNodes := []dialog.Node{
 		{ID: "shill", Text: message, Keyboard: [][]dialog.Button{{{Text: buttonLabel, URL: link}}}},
 	}
 	p := dialog.New(dialogNodes, dialog.WithPrefix("config"))
-	_, err = p.Show(ctx, b, update.Message.Chat.ID, "shill")
+	_, err = p.Show(ctx, b, s.Key.ChatID, "shill")
 	if err != nil {
-		sch.SendMessageAndFinish(shs, ctx, b, shs.ChatID, "sorry an error occurred, please try again 6")
+		tgh.SendMessage(ctx, b, s.Key.ChatID, tgh.T(i18n.ShillError, 6), &models.ReplyParameters{})
 		log.Fatal(err)
-		return
+		return commandhandler.STEP_END, nil
 	}
 
-	sch.UpdateState(shs.ChatID, ShillHandlerState{})
+	return commandhandler.STEP_END, nil
 }
 
-// UpdateState
-func (sch *ShillCommandHandler) UpdateState(chatID int64, shs ShillHandlerState) {
-	state[chatID] = shs
+// FindTweetURL - the first x.com or twitter.com status url in text
+func FindTweetURL(text string) (string, bool) {
+	tweetURL := tweetURLRegex.FindString(text)
+	if tweetURL == "" {
+		return "", false
+	}
+
+	if !strings.HasPrefix(strings.ToLower(tweetURL), "http") {
+		tweetURL = "https://" + tweetURL
+	}
+
+	return tweetURL, true
 }
 
 // isTweetURL
@@ -313,6 +330,17 @@ func (sch *ShillCommandHandler) isTweetURL(rawURL string) bool {
 	return re.MatchString(u.Path)
 }
 
+// parseCommandArgs - split "/shillx[@bot] <tweet-url> <tweet text…>" into the
+// tweet url and text, either may be empty
+func parseCommandArgs(text string) (string, string) {
+	matches := commandArgsRegex.FindStringSubmatch(strings.TrimSpace(text))
+	if matches == nil {
+		return "", ""
+	}
+
+	return matches[1], strings.TrimSpace(matches[2])
+}
+
 // extractTweetID
 func (sch *ShillCommandHandler) extractTweetID(tweetURL string) string {
 	parts := strings.Split(tweetURL, "/")
@@ -320,18 +348,13 @@ func (sch *ShillCommandHandler) extractTweetID(tweetURL string) string {
 }
 
 // generateShillLink
-func (sch *ShillCommandHandler) generateShillLink(chatID int64) (string, error) {
-	shs, ok := state[chatID]
-	if !ok {
-		shs = ShillHandlerState{}
-	}
-
+func (sch *ShillCommandHandler) generateShillLink(s *commandhandler.Session[ShillData]) (string, error) {
 	sl := NewShillLink(sch.mongo)
-	sl.ChatID = chatID
-	sl.TweetID = sch.extractTweetID(shs.tweetLink)
-	sl.TweetLink = shs.tweetLink
-	sl.TweetText = shs.tweetText
-	sl.ReplyType = shs.ReplyType
+	sl.ChatID = s.Key.ChatID
+	sl.TweetID = sch.extractTweetID(s.Data.TweetLink)
+	sl.TweetLink = s.Data.TweetLink
+	sl.TweetText = s.Data.TweetText
+	sl.ReplyType = s.Data.ReplyType
 
 	if err := sl.Insert(sl); err != nil {
 		return "", err
@@ -341,43 +364,31 @@ func (sch *ShillCommandHandler) generateShillLink(chatID int64) (string, error)
 	return fmt.Sprintf("%v/shill/%v", apiUrl, sl.ID.Hex()), nil
 }
 
-// configByChatID
-func (sch *ShillCommandHandler) configByChatID(chatID int64, ctx context.Context, b *bot.Bot) (config.Config, error) {
+// configByChatID - false when the chat can't shill yet, the member has been told why
+func (sch *ShillCommandHandler) configByChatID(ctx context.Context, b *bot.Bot, s *commandhandler.Session[ShillData]) (config.Config, bool, error) {
+	chatID := s.Key.ChatID
+	tgh := sch.TGHelper(b, s)
+
 	c, found, err := config.ConfigByChatID(sch.mongo, chatID)
 	if err != nil {
-		sch.logger.Error(
-			"an error occurred trying to fetch config by chat ID",
-			zap.Int64("chatID", chatID),
-			zap.Error(err),
-		)
-		return c, err
+		return c, false, fmt.Errorf("fetch config by chat ID: %w", err)
 	}
 
 	if !found {
-		sch.tgh.SendErrorNoConfig(ctx, b, chatID)
-		return c, errors.New("config not found")
+		tgh.SendErrorNoConfig(ctx, b, chatID)
+		return c, false, nil
 	}
 
 	if c.Token == "" {
-		sch.tgh.SendErrorNoTokenName(ctx, b, chatID)
-		return c, errors.New("token not set")
+		tgh.SendErrorNoTokenName(ctx, b, chatID)
+		return c, false, nil
 	}
 
-	return c, nil
-}
-
-// setLogger
-func (sch *ShillCommandHandler) SetLogger(logger *zap.Logger) {
-	sch.logger = logger
-}
-
-// setMongo
-func (sch *ShillCommandHandler) SetMongo(mongo *storage.Mongo) {
-	sch.mongo = mongo
-}
+	if !c.PersonaEnabled(sch.replyType) {
+		p, _ := persona.ByName(sch.replyType)
+		tgh.SendMessage(ctx, b, chatID, tgh.T(i18n.ShillPersonaDisabled, p.Command), &models.ReplyParameters{})
+		return c, false, nil
+	}
 
-// State
-func (sch *ShillCommandHandler) State(chatID int64) (ShillHandlerState, bool) {
-	shs, ok := state[chatID]
-	return shs, ok
+	return c, true, nil
 }
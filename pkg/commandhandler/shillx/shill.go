@@ -1,24 +1,112 @@
 package shillx
 
 import (
+	"fmt"
 	"time"
 
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Shill struct {
 	ShillRepository `json:"-" bson:"-"`
 	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	ShillLinkID     primitive.ObjectID `bson:"shillLinkId,omitempty"`
 	ChatID          int64              `bson:"chatId"`
 	TweetID         string             `bson:"tweetId"`
 	Reply           string             `bson:"reply"`
+	Variants        []ShillVariant     `bson:"variants,omitempty"`
+	PickedVariant   *int               `bson:"pickedVariant,omitempty"`
+	PickedStyle     string             `bson:"pickedStyle,omitempty"`
+	Picked          *time.Time         `bson:"picked,omitempty"`
 	Created         time.Time
 }
 
+// ShillVariant - one of several replies offered for the user to pick from
+type ShillVariant struct {
+	Style string `bson:"style"`
+	Reply string `bson:"reply"`
+}
+
 // NewShill
 func NewShill(mongo *storage.Mongo) *Shill {
 	return &Shill{
 		ShillRepository: NewShillRepository(mongo),
 	}
 }
+
+// ShillByID
+func ShillByID(mongo *storage.Mongo, ID string) (*Shill, bool, error) {
+	s := NewShill(mongo)
+
+	objectID, err := primitive.ObjectIDFromHex(ID)
+	if err != nil {
+		return s, false, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: objectID},
+	}
+
+	results, err := s.Find(filter, options.Find())
+	if err != nil {
+		return s, false, err
+	}
+
+	if len(results) == 0 {
+		return s, false, nil
+	}
+
+	s = &results[0]
+	s.ShillRepository = NewShillRepository(mongo)
+
+	return s, true, nil
+}
+
+// ShillsByTweetID - the most recent replies generated for a tweet, from any chat
+func ShillsByTweetID(mongo *storage.Mongo, tweetID string, limit int64) ([]Shill, error) {
+	s := NewShill(mongo)
+
+	filter := bson.D{
+		{Key: "tweetId", Value: tweetID},
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created", Value: -1}}).
+		SetLimit(limit)
+
+	return s.Find(filter, findOptions)
+}
+
+// Replies - the reply and every variant offered
+func (s *Shill) Replies() []string {
+	var replies []string
+	if s.Reply != "" {
+		replies = append(replies, s.Reply)
+	}
+
+	for _, v := range s.Variants {
+		if v.Reply != "" && v.Reply != s.Reply {
+			replies = append(replies, v.Reply)
+		}
+	}
+
+	return replies
+}
+
+// Pick - record the variant the user chose to post
+func (s *Shill) Pick(index int) error {
+	if index < 0 || index >= len(s.Variants) {
+		return fmt.Errorf("variant %d out of range", index)
+	}
+
+	now := time.Now()
+	s.PickedVariant = &index
+	s.PickedStyle = s.Variants[index].Style
+	s.Picked = &now
+	s.Reply = s.Variants[index].Reply
+
+	return s.Update(s)
+}
@@ -13,6 +13,7 @@ import (
 
 type ShillRepository interface {
 	Insert(s *Shill) error
+	Update(s *Shill) error
 	Find(filter bson.D, findOptions *options.FindOptions) ([]Shill, error)
 	Collection() *mongo.Collection
 }
@@ -44,6 +45,17 @@ func (sr *shillRepository) Insert(s *Shill) error {
 	return err
 }
 
+// Update
+func (sr *shillRepository) Update(s *Shill) error {
+	_, err := sr.Collection().ReplaceOne(
+		context.Background(),
+		bson.D{{Key: "_id", Value: s.ID}},
+		s,
+	)
+
+	return err
+}
+
 // Find
 func (sr *shillRepository) Find(filter bson.D, findOptions *options.FindOptions) ([]Shill, error) {
 	var shills []Shill
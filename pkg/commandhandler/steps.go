@@ -0,0 +1,70 @@
+package commandhandler
+
+import (
+	"context"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// StepHistory - the steps a session has been through, persisted with the handler's
+// state so Back returns to the previous step
+type StepHistory []string
+
+// Current - the step the session is on, empty before the first step
+func (sh StepHistory) Current() string {
+	if len(sh) == 0 {
+		return ""
+	}
+
+	return sh[len(sh)-1]
+}
+
+// Push - move on to step. Returning to an earlier step, e.g. a menu, drops the
+// steps taken since so Back never loops.
+func (sh *StepHistory) Push(step string) {
+	for i, s := range *sh {
+		if s == step {
+			*sh = (*sh)[:i+1]
+			return
+		}
+	}
+
+	*sh = append(*sh, step)
+}
+
+// Back - leave the current step, false when there is no previous step
+func (sh *StepHistory) Back() (string, bool) {
+	if len(*sh) < 2 {
+		return "", false
+	}
+
+	*sh = (*sh)[:len(*sh)-1]
+	return sh.Current(), true
+}
+
+// STEP_END - returned by an action or Receive to finish the conversation
+const STEP_END = ""
+
+// Action - runs against a session, e.g. when a button is pressed, and returns the
+// step to show next. Errors are logged, the user asked to try again and the
+// current step shown again.
+type Action[S any] func(ctx context.Context, b *bot.Bot, s *Session[S]) (string, error)
+
+// Goto - an action that just shows step
+func Goto[S any](step string) Action[S] {
+	return func(ctx context.Context, b *bot.Bot, s *Session[S]) (string, error) {
+		return step, nil
+	}
+}
+
+// Step - one prompt of a conversation. Prompt returns the step's message, sent as
+// HTML, and adds its buttons to kb. Steps answered with a reply have a Receive,
+// Validate checks the reply first and its error is shown with the prompt again.
+type Step[S any] struct {
+	Prompt   func(ctx context.Context, s *Session[S], kb *Keyboard[S]) (string, error)
+	Validate func(s *Session[S], message *models.Message) error
+	Receive  func(ctx context.Context, b *bot.Bot, s *Session[S], message *models.Message) (string, error)
+	NoBack   bool
+	NoCancel bool
+}
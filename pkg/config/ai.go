@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/llm"
+)
+
+const (
+	MinTemperature     float32 = 0
+	MaxTemperature     float32 = 2
+	MinPresencePenalty float32 = -2
+	MaxPresencePenalty float32 = 2
+	MinMaxTokens               = 16
+	MaxMaxTokens               = 1024
+)
+
+// AISettings - per chat generation parameters, nil and zero values use the defaults.
+// Temperature and presence penalty are pointers as 0 is a valid setting for both
+type AISettings struct {
+	Model           string   `bson:"model,omitempty"`
+	Temperature     *float32 `bson:"temperature,omitempty"`
+	MaxTokens       int      `bson:"maxTokens,omitempty"`
+	PresencePenalty *float32 `bson:"presencePenalty,omitempty"`
+}
+
+// Request - the generation request for instruction using these settings, a model
+// that has since been removed from llm.models falls back to the default
+func (ai AISettings) Request(instruction string) llm.Request {
+	model := ai.Model
+	if model == "" || !llm.ModelAllowed(model) {
+		model = llm.Model()
+	}
+
+	return llm.Request{
+		Model:           model,
+		Instruction:     instruction,
+		Temperature:     ai.Temperature,
+		MaxTokens:       ai.MaxTokens,
+		PresencePenalty: ai.PresencePenalty,
+	}
+}
+
+// Validate
+func (ai AISettings) Validate() error {
+	if err := ValidateModel(ai.Model); err != nil {
+		return err
+	}
+
+	if ai.Temperature != nil {
+		if err := ValidateTemperature(*ai.Temperature); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateMaxTokens(ai.MaxTokens); err != nil {
+		return err
+	}
+
+	if ai.PresencePenalty != nil {
+		return ValidatePresencePenalty(*ai.PresencePenalty)
+	}
+
+	return nil
+}
+
+// ValidateModel - the model must be in the llm.models allowlist
+func ValidateModel(model string) error {
+	if model != "" && !llm.ModelAllowed(model) {
+		return fmt.Errorf("model %q is not available", model)
+	}
+
+	return nil
+}
+
+// ValidateTemperature
+func ValidateTemperature(temperature float32) error {
+	if temperature < MinTemperature || temperature > MaxTemperature {
+		return fmt.Errorf("temperature must be between %.1f and %.1f", MinTemperature, MaxTemperature)
+	}
+
+	return nil
+}
+
+// ValidateMaxTokens - 0 uses the provider's default
+func ValidateMaxTokens(maxTokens int) error {
+	if maxTokens != 0 && (maxTokens < MinMaxTokens || maxTokens > MaxMaxTokens) {
+		return fmt.Errorf("max tokens must be between %d and %d", MinMaxTokens, MaxMaxTokens)
+	}
+
+	return nil
+}
+
+// ValidatePresencePenalty
+func ValidatePresencePenalty(presencePenalty float32) error {
+	if presencePenalty < MinPresencePenalty || presencePenalty > MaxPresencePenalty {
+		return fmt.Errorf("presence penalty must be between %.1f and %.1f", MinPresencePenalty, MaxPresencePenalty)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import "fmt"
+
+const (
+	MaxWordListLength = 100
+	MaxWordLength     = 64
+)
+
+// BrandSafety - per chat rules every reply must follow
+type BrandSafety struct {
+	ForbiddenPhrases []string `bson:"forbiddenPhrases,omitempty"`
+	Competitors      []string `bson:"competitors,omitempty"`
+	NFASuffix        bool     `bson:"nfaSuffix,omitempty"`
+}
+
+// ValidateWordList - a list of words or phrases set from /config
+func ValidateWordList(words []string) error {
+	if len(words) == 0 {
+		return fmt.Errorf("at least one word is required")
+	}
+
+	if len(words) > MaxWordListLength {
+		return fmt.Errorf("at most %d words are allowed", MaxWordListLength)
+	}
+
+	for _, word := range words {
+		if len(word) > MaxWordLength {
+			return fmt.Errorf("%q is longer than %d characters", word, MaxWordLength)
+		}
+	}
+
+	return nil
+}
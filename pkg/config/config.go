@@ -17,10 +17,28 @@ type Config struct {
 	Community        string             `bson:"community"`
 	Hashtags         string             `bson:"hashtags"`
 	Cashtags         string             `bson:"cashtags"`
+	AutoDetectLinks  bool               `bson:"autoDetectLinks"`
+	DisabledPersonas []string           `bson:"disabledPersonas"`
+	BannedWords      []string           `bson:"bannedWords"`
+	AI               AISettings         `bson:"ai"`
+	BrandSafety      BrandSafety        `bson:"brandSafety"`
+	Language         LanguageSettings   `bson:"language"`
+	Locale           string             `bson:"locale,omitempty"`
+	Managers         []int64            `bson:"managers,omitempty"`
+	Permissions      Permissions        `bson:"permissions"`
+	Quota            QuotaSettings      `bson:"quota"`
+	StateTTL         time.Duration      `bson:"stateTtl,omitempty"`
 	Created          time.Time
 	Updated          time.Time
 }
 
+// QuotaSettings - generations allowed per chat, 0 uses the default from quota.daily
+// and quota.monthly and a negative value is unlimited
+type QuotaSettings struct {
+	Daily   int `bson:"daily,omitempty"`
+	Monthly int `bson:"monthly,omitempty"`
+}
+
 // NewShill
 func NewConfig(mongo *storage.Mongo) Config {
 	return Config{
@@ -51,3 +69,30 @@ func ConfigByChatID(mongo *storage.Mongo, ChatID int64) (Config, bool, error) {
 
 	return sl, true, nil
 }
+
+// PersonaEnabled - personas are enabled unless the chat has disabled them
+func (c Config) PersonaEnabled(name string) bool {
+	for _, disabled := range c.DisabledPersonas {
+		if disabled == name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TogglePersona
+func (c *Config) TogglePersona(name string) {
+	if c.PersonaEnabled(name) {
+		c.DisabledPersonas = append(c.DisabledPersonas, name)
+		return
+	}
+
+	enabled := c.DisabledPersonas[:0]
+	for _, disabled := range c.DisabledPersonas {
+		if disabled != name {
+			enabled = append(enabled, disabled)
+		}
+	}
+	c.DisabledPersonas = enabled
+}
@@ -0,0 +1,8 @@
+package config
+
+// LanguageSettings - languages replies are written in. Replies follow the tweet's
+// language out of the preferred languages, a forced language overrides detection.
+type LanguageSettings struct {
+	Preferred []string `bson:"preferred,omitempty"`
+	Forced    string   `bson:"forced,omitempty"`
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	MaxManagers = 20
+)
+
+// IsManager - bot managers can use /config without being chat admins
+func (c Config) IsManager(userID int64) bool {
+	for _, manager := range c.Managers {
+		if manager == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddManager - false when the user is already a manager
+func (c *Config) AddManager(userID int64) bool {
+	if c.IsManager(userID) {
+		return false
+	}
+
+	c.Managers = append(c.Managers, userID)
+	return true
+}
+
+// ParseUserIDs - a comma or space separated list of Telegram user IDs
+func ParseUserIDs(list string) ([]int64, error) {
+	var userIDs []int64
+	for _, field := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		userID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || userID <= 0 {
+			return nil, fmt.Errorf("%q is not a user ID", field)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if len(userIDs) == 0 {
+		return nil, fmt.Errorf("at least one user ID is required")
+	}
+
+	return userIDs, nil
+}
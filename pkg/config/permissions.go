@@ -0,0 +1,111 @@
+package config
+
+import "fmt"
+
+const (
+	POLICY_EVERYONE  = "everyone"
+	POLICY_ADMINS    = "admins"
+	POLICY_ALLOWLIST = "allowlist"
+	POLICY_ROLE      = "role"
+)
+
+// Permissions - who may run each command in the chat, keyed by the command without
+// the slash e.g. "shillx". Roles tag members so a policy can allow all of them.
+type Permissions struct {
+	Commands map[string]CommandPolicy `bson:"commands,omitempty"`
+	Roles    map[string][]int64       `bson:"roles,omitempty"`
+}
+
+// CommandPolicy - chat admins and bot managers can run every command regardless
+// of the policy
+type CommandPolicy struct {
+	Policy string  `bson:"policy"`
+	Users  []int64 `bson:"users,omitempty"`
+	Role   string  `bson:"role,omitempty"`
+}
+
+// Policies
+func Policies() []string {
+	return []string{POLICY_EVERYONE, POLICY_ADMINS, POLICY_ALLOWLIST, POLICY_ROLE}
+}
+
+// CommandPolicy - the chat's policy for command, or fallback when it hasn't set one
+func (c Config) CommandPolicy(command string, fallback string) CommandPolicy {
+	if policy, ok := c.Permissions.Commands[command]; ok {
+		return policy
+	}
+
+	return CommandPolicy{Policy: fallback}
+}
+
+// SetCommandPolicy
+func (c *Config) SetCommandPolicy(command string, policy CommandPolicy) {
+	if c.Permissions.Commands == nil {
+		c.Permissions.Commands = map[string]CommandPolicy{}
+	}
+
+	c.Permissions.Commands[command] = policy
+}
+
+// HasRole
+func (c Config) HasRole(role string, userID int64) bool {
+	for _, member := range c.Permissions.Roles[role] {
+		if member == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetRole - a role without members is removed
+func (c *Config) SetRole(role string, userIDs []int64) {
+	if len(userIDs) == 0 {
+		delete(c.Permissions.Roles, role)
+		return
+	}
+
+	if c.Permissions.Roles == nil {
+		c.Permissions.Roles = map[string][]int64{}
+	}
+
+	c.Permissions.Roles[role] = userIDs
+}
+
+// Allows - whether a member who isn't an admin or bot manager may run the command
+func (cp CommandPolicy) Allows(c Config, userID int64) bool {
+	switch cp.Policy {
+	case POLICY_EVERYONE:
+		return true
+	case POLICY_ALLOWLIST:
+		for _, user := range cp.Users {
+			if user == userID {
+				return true
+			}
+		}
+	case POLICY_ROLE:
+		return c.HasRole(cp.Role, userID)
+	}
+
+	return false
+}
+
+// ValidateCommandPolicy
+func ValidateCommandPolicy(cp CommandPolicy) error {
+	switch cp.Policy {
+	case POLICY_EVERYONE, POLICY_ADMINS:
+		return nil
+	case POLICY_ALLOWLIST:
+		if len(cp.Users) == 0 {
+			return fmt.Errorf("the allowlist policy needs at least one user")
+		}
+		return nil
+	case POLICY_ROLE:
+		if cp.Role == "" {
+			return fmt.Errorf("the role policy needs a role")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown policy %q", cp.Policy)
+}
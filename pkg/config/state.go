@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+)
+
+// StateTTL - how long the chat's conversational state survives without being
+// saved, the chat's own state ttl falling back to state.ttl. Without a database
+// e.g. in tests the default is used
+func StateTTL(mongo *storage.Mongo, chatID int64) time.Duration {
+	if mongo != nil {
+		c, found, err := ConfigByChatID(mongo, chatID)
+		if err == nil && found && c.StateTTL > 0 {
+			return c.StateTTL
+		}
+	}
+
+	return storage.DefaultStateTTL()
+}
+
+// ValidateStateTTL - 0 is stored as unset so a chat's own ttl must be positive
+func ValidateStateTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("state ttl must be positive, got %s", ttl)
+	}
+
+	return nil
+}
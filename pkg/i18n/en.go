@@ -0,0 +1,168 @@
+package i18n
+
+var en = catalogue{
+	ButtonBack:   "Back",
+	ButtonCancel: "Cancel",
+	ButtonClear:  "Clear",
+	ButtonDone:   "Done",
+	On:           "On",
+	Off:          "Off",
+	Cancelled:    "cancelled",
+	TimedOut:     "timed out, run the command again when you're ready",
+	NotAllowed:   "You don't have permission to use /%s in %s.",
+
+	ErrorTryAgain:    "Oops, looks like we're having trouble, please try again.",
+	ErrorNoConfig:    "No config found, please run /config.",
+	ErrorNoTokenName: "Token name not set, please run /config and Set Token Name.",
+
+	ShillRequestTweetLink: "Please provide the tweet link",
+	ShillRequestTweetText: "Please provide the original tweet text",
+	ShillPreviousTweetURL: "Please provide the tweet link, the last one was\n%s",
+	ShillInvalidTweetURL:  "not a valid tweet url, please start again",
+	ShillRetryTweetURL:    "not a valid tweet url, please try again",
+	ShillError:            "sorry an error occurred, please try again %d",
+	ShillReady: `%v
+
+Let's go %v baby!!
+
+Just click on the %v NOW button to generate your own, AI %v reply.%v
+`,
+	ShillPersonaDisabled: "/%s is disabled in this chat, an admin can enable it in /config",
+
+	UsageTitle:     "Usage",
+	UsageToday:     "Today",
+	UsageThisMonth: "This month",
+	UsageAllTime:   "All time",
+	UsagePeriod:    "<b>%s:</b> %d generations, %d prompt + %d completion tokens, ~$%.4f",
+	UsageModel:     "%s: %d generations, ~$%.4f",
+
+	ConfigMainMenu: `Current configuration:
+
+<b>Token name:</b> %s
+<b>Hashtag(s):</b> %s
+<b>Cashtag(s):</b> %s
+<b>Community:</b> %s
+<b>Auto-detect tweet links:</b> %s
+<b>Personas:</b> %s
+<b>Banned words:</b> %s
+<b>Bot managers:</b> %s
+<b>Bot language:</b> %s`,
+	ConfigNotSet:          "<i>Not set</i>",
+	ConfigNone:            "<i>None</i>",
+	ConfigAutomatic:       "Automatic",
+	ConfigPersonasPrompt:  "Choose which personas members can use in this chat.",
+	ConfigLocalePrompt:    "Which language should I use for my messages? Automatic uses each member's Telegram language.",
+	ConfigTokenNamePrompt: "What is the name of your token?",
+	ConfigHashtagsPrompt: `What hashtag should I use when shilling your token?
+
+You can set multiple hashtags but I'll only use one or two at a time.
+
+Multiple hashtags should be separated by spaces e.g.
+#MyAwesomeToken #MYTOKENTOTHEMOON #MyTokenIsTheBest
+
+Make sure your primary hashtag is set first.`,
+	ConfigCashtagsPrompt: `What cashtag should I use when shilling your token?
+
+e.g. $MYTOKEN
+`,
+	ConfigCommunityPrompt: `Describe your community (max. 500 chars).
+
+Your description will help me create better shill responses.`,
+	ConfigInvalidTokenName: "Invalid token name, please try again",
+	ConfigInvalidHashtags:  "Invalid hashtags, please try again",
+	ConfigInvalidCashtags:  "Invalid cashtags, please try again",
+	ConfigInvalidCommunity: "Invalid description, please try again",
+
+	ConfigButtonTokenName:       "Set Token Name",
+	ConfigButtonCommunity:       "Describe Community",
+	ConfigButtonHashtags:        "Set Hashtag(s)",
+	ConfigButtonCashtags:        "Set Cashtag(s)",
+	ConfigButtonAutoDetectLinks: "Auto-detect Links: %s",
+	ConfigButtonPersonas:        "Personas",
+	ConfigButtonAISettings:      "AI Settings",
+	ConfigButtonReplyLanguage:   "Reply Language",
+	ConfigButtonBannedWords:     "Banned Words",
+	ConfigButtonBrandSafety:     "Brand Safety",
+	ConfigButtonManagers:        "Bot Managers",
+	ConfigButtonLocale:          "Bot Language",
+	ConfigButtonAutomaticLocale: "Automatic (Telegram language)",
+
+	ConfigDefault:      "<i>Default</i>",
+	ConfigDefaultModel: "%s <i>(default)</i>",
+	ConfigInvalidValue: "Invalid value, please try again",
+	ConfigAISettings: `AI settings:
+
+<b>Model:</b> %s
+<b>Temperature:</b> %s
+<b>Max tokens:</b> %s
+<b>Presence penalty:</b> %s`,
+	ConfigAIModelPrompt: "Which model should I use for this chat?",
+	ConfigAITemperaturePrompt: `What temperature should I use? (%.1f to %.1f)
+
+Lower values give more focused replies, higher values more creative ones. Press Clear to use the default.`,
+	ConfigAIMaxTokensPrompt: `What is the maximum number of tokens a reply may use? (%d to %d)
+
+Send 0 to use the default.`,
+	ConfigAIPresencePenaltyPrompt: `What presence penalty should I use? (%.1f to %.1f)
+
+Higher values make replies more likely to move on to new topics. Press Clear to use the default.`,
+	ConfigButtonModel:           "Model",
+	ConfigButtonDefaultModel:    "Default (%s)",
+	ConfigButtonTemperature:     "Temperature",
+	ConfigButtonMaxTokens:       "Max Tokens",
+	ConfigButtonPresencePenalty: "Presence Penalty",
+	ConfigButtonResetDefaults:   "Reset to Defaults",
+
+	ConfigBrandSafety: `Brand safety:
+
+<b>Forbidden phrases:</b> %s
+<b>Competitors:</b> %s
+<b>NFA suffix:</b> %s
+
+Replies breaking these rules are regenerated, or rejected if I can't avoid them.`,
+	ConfigForbiddenPhrasesPrompt: `Which words or phrases should replies never use?
+
+Separate them with commas e.g.
+guaranteed, 100x, risk free`,
+	ConfigCompetitorsPrompt: `Which tokens should replies never name?
+
+Separate them with commas e.g.
+$DOGE, $SHIB, PEPE`,
+	ConfigButtonForbiddenPhrases: "Forbidden Phrases",
+	ConfigButtonCompetitors:      "Competitors",
+	ConfigButtonNFASuffix:        "NFA Suffix: %s",
+
+	ConfigReplyLanguage: `Reply language:
+
+<b>Preferred languages:</b> %s
+<b>Forced language:</b> %s
+
+Replies are written in the tweet's language when it's one of your preferred languages, otherwise in the first preferred language. A forced language is always used.`,
+	ConfigPreferredLanguagesPrompt: `Which languages should I reply in?
+
+Send two letter language codes separated by commas, your main language first e.g.
+en, es, tr`,
+	ConfigForcedLanguagePrompt: `Which language should every reply be written in, whatever the tweet's language?
+
+Send a two letter language code e.g. en`,
+	ConfigInvalidLanguage:          "Invalid language, please try again",
+	ConfigButtonPreferredLanguages: "Preferred Languages",
+	ConfigButtonForcedLanguage:     "Force Language",
+
+	ConfigBannedWordsPrompt: `Which words or phrases should never appear in a reply?
+
+Separate them with commas e.g.
+scam, rug pull, guaranteed returns
+
+Replies containing any of them are regenerated, or rejected if I can't avoid them.`,
+	ConfigInvalidBannedWords: "Invalid banned words, please try again",
+
+	ConfigManagersPrompt: `Who else can use /config? Chat admins always can.
+
+Reply to a message from the member to add them, or send the Telegram user IDs of every manager separated by commas e.g.
+123456789, 987654321`,
+	ConfigInvalidManagers: "Invalid managers, please try again",
+
+	AutoDetectPrompt: "Tweet spotted! Want an AI reply?\n%s",
+	AutoDetectButton: "%s it",
+}
@@ -0,0 +1,168 @@
+package i18n
+
+var es = catalogue{
+	ButtonBack:   "Atrás",
+	ButtonCancel: "Cancelar",
+	ButtonClear:  "Borrar",
+	ButtonDone:   "Listo",
+	On:           "Activado",
+	Off:          "Desactivado",
+	Cancelled:    "cancelado",
+	TimedOut:     "tiempo agotado, vuelve a ejecutar el comando cuando estés listo",
+	NotAllowed:   "No tienes permiso para usar /%s en %s.",
+
+	ErrorTryAgain:    "Vaya, parece que tenemos problemas, inténtalo de nuevo.",
+	ErrorNoConfig:    "No hay configuración, ejecuta /config.",
+	ErrorNoTokenName: "El nombre del token no está configurado, ejecuta /config y elige Nombre del token.",
+
+	ShillRequestTweetLink: "Envía el enlace del tweet",
+	ShillRequestTweetText: "Envía el texto original del tweet",
+	ShillPreviousTweetURL: "Envía el enlace del tweet, el anterior era\n%s",
+	ShillInvalidTweetURL:  "no es un enlace de tweet válido, empieza de nuevo",
+	ShillRetryTweetURL:    "no es un enlace de tweet válido, inténtalo de nuevo",
+	ShillError:            "lo sentimos, se produjo un error, inténtalo de nuevo %d",
+	ShillReady: `%v
+
+¡¡Vamos, modo %v!!
+
+Pulsa el botón %v NOW para generar tu propia respuesta %v con IA.%v
+`,
+	ShillPersonaDisabled: "/%s está desactivado en este chat, un administrador puede activarlo en /config",
+
+	UsageTitle:     "Uso",
+	UsageToday:     "Hoy",
+	UsageThisMonth: "Este mes",
+	UsageAllTime:   "Desde siempre",
+	UsagePeriod:    "<b>%s:</b> %d generaciones, %d tokens de prompt + %d de respuesta, ~$%.4f",
+	UsageModel:     "%s: %d generaciones, ~$%.4f",
+
+	ConfigMainMenu: `Configuración actual:
+
+<b>Nombre del token:</b> %s
+<b>Hashtag(s):</b> %s
+<b>Cashtag(s):</b> %s
+<b>Comunidad:</b> %s
+<b>Detectar enlaces de tweets:</b> %s
+<b>Personajes:</b> %s
+<b>Palabras prohibidas:</b> %s
+<b>Gestores del bot:</b> %s
+<b>Idioma del bot:</b> %s`,
+	ConfigNotSet:          "<i>Sin configurar</i>",
+	ConfigNone:            "<i>Ninguno</i>",
+	ConfigAutomatic:       "Automático",
+	ConfigPersonasPrompt:  "Elige qué personajes pueden usar los miembros en este chat.",
+	ConfigLocalePrompt:    "¿En qué idioma debo escribir mis mensajes? Automático usa el idioma de Telegram de cada miembro.",
+	ConfigTokenNamePrompt: "¿Cómo se llama tu token?",
+	ConfigHashtagsPrompt: `¿Qué hashtag debo usar al promocionar tu token?
+
+Puedes configurar varios hashtags, pero solo usaré uno o dos a la vez.
+
+Separa los hashtags con espacios, p. ej.
+#MiTokenIncreible #MITOKENALALUNA #MiTokenEsElMejor
+
+Asegúrate de poner primero tu hashtag principal.`,
+	ConfigCashtagsPrompt: `¿Qué cashtag debo usar al promocionar tu token?
+
+p. ej. $MITOKEN
+`,
+	ConfigCommunityPrompt: `Describe tu comunidad (máx. 500 caracteres).
+
+Tu descripción me ayudará a crear mejores respuestas.`,
+	ConfigInvalidTokenName: "Nombre de token no válido, inténtalo de nuevo",
+	ConfigInvalidHashtags:  "Hashtags no válidos, inténtalo de nuevo",
+	ConfigInvalidCashtags:  "Cashtags no válidos, inténtalo de nuevo",
+	ConfigInvalidCommunity: "Descripción no válida, inténtalo de nuevo",
+
+	ConfigButtonTokenName:       "Nombre del token",
+	ConfigButtonCommunity:       "Describir comunidad",
+	ConfigButtonHashtags:        "Hashtag(s)",
+	ConfigButtonCashtags:        "Cashtag(s)",
+	ConfigButtonAutoDetectLinks: "Detectar enlaces: %s",
+	ConfigButtonPersonas:        "Personajes",
+	ConfigButtonAISettings:      "Ajustes de IA",
+	ConfigButtonReplyLanguage:   "Idioma de respuesta",
+	ConfigButtonBannedWords:     "Palabras prohibidas",
+	ConfigButtonBrandSafety:     "Seguridad de marca",
+	ConfigButtonManagers:        "Gestores del bot",
+	ConfigButtonLocale:          "Idioma del bot",
+	ConfigButtonAutomaticLocale: "Automático (idioma de Telegram)",
+
+	ConfigDefault:      "<i>Predeterminado</i>",
+	ConfigDefaultModel: "%s <i>(predeterminado)</i>",
+	ConfigInvalidValue: "Valor no válido, inténtalo de nuevo",
+	ConfigAISettings: `Ajustes de IA:
+
+<b>Modelo:</b> %s
+<b>Temperatura:</b> %s
+<b>Máx. tokens:</b> %s
+<b>Penalización por presencia:</b> %s`,
+	ConfigAIModelPrompt: "¿Qué modelo debo usar en este chat?",
+	ConfigAITemperaturePrompt: `¿Qué temperatura debo usar? (%.1f a %.1f)
+
+Los valores bajos dan respuestas más centradas y los altos más creativas. Pulsa Borrar para usar el valor predeterminado.`,
+	ConfigAIMaxTokensPrompt: `¿Cuántos tokens puede usar como máximo una respuesta? (%d a %d)
+
+Envía 0 para usar el valor predeterminado.`,
+	ConfigAIPresencePenaltyPrompt: `¿Qué penalización por presencia debo usar? (%.1f a %.1f)
+
+Los valores altos hacen que las respuestas pasen antes a temas nuevos. Pulsa Borrar para usar el valor predeterminado.`,
+	ConfigButtonModel:           "Modelo",
+	ConfigButtonDefaultModel:    "Predeterminado (%s)",
+	ConfigButtonTemperature:     "Temperatura",
+	ConfigButtonMaxTokens:       "Máx. tokens",
+	ConfigButtonPresencePenalty: "Penalización por presencia",
+	ConfigButtonResetDefaults:   "Restablecer valores",
+
+	ConfigBrandSafety: `Seguridad de marca:
+
+<b>Frases prohibidas:</b> %s
+<b>Competidores:</b> %s
+<b>Sufijo NFA:</b> %s
+
+Las respuestas que incumplan estas reglas se regeneran, o se rechazan si no puedo evitarlo.`,
+	ConfigForbiddenPhrasesPrompt: `¿Qué palabras o frases no deben usar nunca las respuestas?
+
+Sepáralas con comas, p. ej.
+garantizado, 100x, sin riesgo`,
+	ConfigCompetitorsPrompt: `¿Qué tokens no deben nombrar nunca las respuestas?
+
+Sepáralos con comas, p. ej.
+$DOGE, $SHIB, PEPE`,
+	ConfigButtonForbiddenPhrases: "Frases prohibidas",
+	ConfigButtonCompetitors:      "Competidores",
+	ConfigButtonNFASuffix:        "Sufijo NFA: %s",
+
+	ConfigReplyLanguage: `Idioma de respuesta:
+
+<b>Idiomas preferidos:</b> %s
+<b>Idioma forzado:</b> %s
+
+Las respuestas se escriben en el idioma del tweet si es uno de tus idiomas preferidos, si no en el primer idioma preferido. Un idioma forzado se usa siempre.`,
+	ConfigPreferredLanguagesPrompt: `¿En qué idiomas debo responder?
+
+Envía códigos de idioma de dos letras separados por comas, tu idioma principal primero, p. ej.
+es, en, tr`,
+	ConfigForcedLanguagePrompt: `¿En qué idioma debe escribirse cada respuesta, sea cual sea el idioma del tweet?
+
+Envía un código de idioma de dos letras, p. ej. es`,
+	ConfigInvalidLanguage:          "Idioma no válido, inténtalo de nuevo",
+	ConfigButtonPreferredLanguages: "Idiomas preferidos",
+	ConfigButtonForcedLanguage:     "Forzar idioma",
+
+	ConfigBannedWordsPrompt: `¿Qué palabras o frases no deben aparecer nunca en una respuesta?
+
+Sepáralas con comas, p. ej.
+estafa, rug pull, rentabilidad garantizada
+
+Las respuestas que contengan alguna se regeneran, o se rechazan si no puedo evitarlo.`,
+	ConfigInvalidBannedWords: "Palabras prohibidas no válidas, inténtalo de nuevo",
+
+	ConfigManagersPrompt: `¿Quién más puede usar /config? Los administradores del chat siempre pueden.
+
+Responde a un mensaje del miembro para añadirlo, o envía los ID de usuario de Telegram de todos los gestores separados por comas, p. ej.
+123456789, 987654321`,
+	ConfigInvalidManagers: "Gestores no válidos, inténtalo de nuevo",
+
+	AutoDetectPrompt: "¡Tweet detectado! ¿Quieres una respuesta con IA?\n%s",
+	AutoDetectButton: "%s",
+}
@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const DefaultLocale = "en"
+
+// catalogue - the messages of a locale, values may contain fmt verbs
+type catalogue map[Key]string
+
+var (
+	catalogues = map[string]catalogue{
+		"en": en,
+		"es": es,
+	}
+
+	// names - each locale's name in its own language
+	names = map[string]string{
+		"en": "English",
+		"es": "Español",
+	}
+)
+
+// T - the message for key in locale, falling back to the default locale and
+// then the key itself. args are formatted into the message.
+func T(locale string, key Key, args ...any) string {
+	message, ok := catalogues[locale][key]
+	if !ok {
+		message, ok = catalogues[DefaultLocale][key]
+	}
+
+	if !ok {
+		return string(key)
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+// Locales - every locale in the catalogue, the default first
+func Locales() []string {
+	var locales []string
+	for locale := range catalogues {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+
+	return append([]string{DefaultLocale}, locales...)
+}
+
+// Supported
+func Supported(locale string) bool {
+	_, ok := catalogues[locale]
+	return ok
+}
+
+// Name - the locale's name in its own language
+func Name(locale string) string {
+	if name, ok := names[locale]; ok {
+		return name
+	}
+
+	return locale
+}
+
+// Resolve - the chat's configured locale when set, otherwise the locale of the
+// user's Telegram language_code e.g. "es" for "es-ES", otherwise the default
+func Resolve(configured string, languageCode string) string {
+	if Supported(configured) {
+		return configured
+	}
+
+	base, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	if Supported(base) {
+		return base
+	}
+
+	return DefaultLocale
+}
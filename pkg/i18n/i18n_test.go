@@ -0,0 +1,53 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbRegex = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestEveryKeyInEveryLocale(t *testing.T) {
+	for locale, c := range catalogues {
+		for key := range catalogues[DefaultLocale] {
+			if _, ok := c[key]; !ok {
+				t.Errorf("locale %q is missing key %q", locale, key)
+			}
+		}
+
+		for key := range c {
+			if _, ok := catalogues[DefaultLocale][key]; !ok {
+				t.Errorf("locale %q has key %q which is not in the default locale", locale, key)
+			}
+		}
+	}
+}
+
+func TestFormatVerbsMatch(t *testing.T) {
+	for locale, c := range catalogues {
+		for key, message := range c {
+			want := verbRegex.FindAllString(catalogues[DefaultLocale][key], -1)
+			got := verbRegex.FindAllString(message, -1)
+
+			if len(got) != len(want) {
+				t.Errorf("locale %q key %q has verbs %v, want %v", locale, key, got, want)
+				continue
+			}
+
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("locale %q key %q has verbs %v, want %v", locale, key, got, want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestEveryLocaleHasAName(t *testing.T) {
+	for _, locale := range Locales() {
+		if _, ok := names[locale]; !ok {
+			t.Errorf("locale %q has no name", locale)
+		}
+	}
+}
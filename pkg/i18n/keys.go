@@ -0,0 +1,111 @@
+package i18n
+
+// Key - identifies a message in the catalogue
+type Key string
+
+// common
+const (
+	ButtonBack   Key = "button.back"
+	ButtonCancel Key = "button.cancel"
+	ButtonClear  Key = "button.clear"
+	ButtonDone   Key = "button.done"
+	On           Key = "common.on"
+	Off          Key = "common.off"
+	Cancelled    Key = "common.cancelled"
+	TimedOut     Key = "common.timedOut"
+	NotAllowed   Key = "common.notAllowed"
+
+	ErrorTryAgain    Key = "error.tryAgain"
+	ErrorNoConfig    Key = "error.noConfig"
+	ErrorNoTokenName Key = "error.noTokenName"
+)
+
+// shill
+const (
+	ShillRequestTweetLink Key = "shill.requestTweetLink"
+	ShillRequestTweetText Key = "shill.requestTweetText"
+	ShillPreviousTweetURL Key = "shill.previousTweetUrl"
+	ShillInvalidTweetURL  Key = "shill.invalidTweetUrl"
+	ShillRetryTweetURL    Key = "shill.retryTweetUrl"
+	ShillError            Key = "shill.error"
+	ShillReady            Key = "shill.ready"
+	ShillPersonaDisabled  Key = "shill.personaDisabled"
+)
+
+// usage
+const (
+	UsageTitle     Key = "usage.title"
+	UsageToday     Key = "usage.today"
+	UsageThisMonth Key = "usage.thisMonth"
+	UsageAllTime   Key = "usage.allTime"
+	UsagePeriod    Key = "usage.period"
+	UsageModel     Key = "usage.model"
+)
+
+// config
+const (
+	ConfigMainMenu              Key = "config.mainMenu"
+	ConfigNotSet                Key = "config.notSet"
+	ConfigNone                  Key = "config.none"
+	ConfigAutomatic             Key = "config.automatic"
+	ConfigPersonasPrompt        Key = "config.personasPrompt"
+	ConfigLocalePrompt          Key = "config.localePrompt"
+	ConfigTokenNamePrompt       Key = "config.tokenNamePrompt"
+	ConfigHashtagsPrompt        Key = "config.hashtagsPrompt"
+	ConfigCashtagsPrompt        Key = "config.cashtagsPrompt"
+	ConfigCommunityPrompt       Key = "config.communityPrompt"
+	ConfigInvalidTokenName      Key = "config.invalidTokenName"
+	ConfigInvalidHashtags       Key = "config.invalidHashtags"
+	ConfigInvalidCashtags       Key = "config.invalidCashtags"
+	ConfigInvalidCommunity      Key = "config.invalidCommunity"
+	ConfigButtonTokenName       Key = "config.button.tokenName"
+	ConfigButtonCommunity       Key = "config.button.community"
+	ConfigButtonHashtags        Key = "config.button.hashtags"
+	ConfigButtonCashtags        Key = "config.button.cashtags"
+	ConfigButtonAutoDetectLinks Key = "config.button.autoDetectLinks"
+	ConfigButtonPersonas        Key = "config.button.personas"
+	ConfigButtonAISettings      Key = "config.button.aiSettings"
+	ConfigButtonReplyLanguage   Key = "config.button.replyLanguage"
+	ConfigButtonBannedWords     Key = "config.button.bannedWords"
+	ConfigButtonBrandSafety     Key = "config.button.brandSafety"
+	ConfigButtonManagers        Key = "config.button.managers"
+	ConfigButtonLocale          Key = "config.button.locale"
+	ConfigButtonAutomaticLocale Key = "config.button.automaticLocale"
+
+	ConfigDefault                  Key = "config.default"
+	ConfigDefaultModel             Key = "config.defaultModel"
+	ConfigInvalidValue             Key = "config.invalidValue"
+	ConfigAISettings               Key = "config.aiSettings"
+	ConfigAIModelPrompt            Key = "config.aiModelPrompt"
+	ConfigAITemperaturePrompt      Key = "config.aiTemperaturePrompt"
+	ConfigAIMaxTokensPrompt        Key = "config.aiMaxTokensPrompt"
+	ConfigAIPresencePenaltyPrompt  Key = "config.aiPresencePenaltyPrompt"
+	ConfigButtonModel              Key = "config.button.model"
+	ConfigButtonDefaultModel       Key = "config.button.defaultModel"
+	ConfigButtonTemperature        Key = "config.button.temperature"
+	ConfigButtonMaxTokens          Key = "config.button.maxTokens"
+	ConfigButtonPresencePenalty    Key = "config.button.presencePenalty"
+	ConfigButtonResetDefaults      Key = "config.button.resetDefaults"
+	ConfigBrandSafety              Key = "config.brandSafety"
+	ConfigForbiddenPhrasesPrompt   Key = "config.forbiddenPhrasesPrompt"
+	ConfigCompetitorsPrompt        Key = "config.competitorsPrompt"
+	ConfigButtonForbiddenPhrases   Key = "config.button.forbiddenPhrases"
+	ConfigButtonCompetitors        Key = "config.button.competitors"
+	ConfigButtonNFASuffix          Key = "config.button.nfaSuffix"
+	ConfigReplyLanguage            Key = "config.replyLanguage"
+	ConfigPreferredLanguagesPrompt Key = "config.preferredLanguagesPrompt"
+	ConfigForcedLanguagePrompt     Key = "config.forcedLanguagePrompt"
+	ConfigInvalidLanguage          Key = "config.invalidLanguage"
+	ConfigButtonPreferredLanguages Key = "config.button.preferredLanguages"
+	ConfigButtonForcedLanguage     Key = "config.button.forcedLanguage"
+	ConfigBannedWordsPrompt        Key = "config.bannedWordsPrompt"
+	ConfigInvalidBannedWords       Key = "config.invalidBannedWords"
+	ConfigManagersPrompt           Key = "config.managersPrompt"
+	ConfigInvalidManagers          Key = "config.invalidManagers"
+)
+
+// auto-detect
+const (
+	AutoDetectPrompt Key = "autoDetect.prompt"
+	AutoDetectButton Key = "autoDetect.button"
+)
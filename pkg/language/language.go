@@ -0,0 +1,166 @@
+package language
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/abadojack/whatlanggo"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultMinConfidence = 0.2
+	// shorter texts e.g. "gm frens" can't be detected with any confidence
+	minLetters = 12
+)
+
+var (
+	urlRegex = regexp.MustCompile(`(?i)https?://\S+`)
+	// tags and mentions are usually english or names so they're left out of detection
+	tagRegex = regexp.MustCompile(`[#$@][\p{L}\p{N}_]+`)
+
+	// defaultCandidates - languages tweets are detected as when language.candidates
+	// is unset, limiting the candidates stops short tweets matching obscure languages
+	defaultCandidates = []string{
+		"en", "es", "pt", "fr", "de", "it", "nl", "pl", "tr", "ru", "uk",
+		"ar", "hi", "id", "vi", "th", "ja", "ko", "zh",
+	}
+)
+
+// Language - a language replies can be written in
+type Language struct {
+	Code string
+	Name string
+}
+
+// ByCode - the language for an ISO 639-1 code e.g. "es"
+func ByCode(code string) (Language, bool) {
+	lang, ok := lookup(code)
+	if !ok {
+		return Language{}, false
+	}
+
+	return Language{Code: lang.Iso6391(), Name: lang.String()}, true
+}
+
+// Candidates - ISO 639-1 codes of the languages tweets are detected as, configured
+// by language.candidates
+func Candidates() []string {
+	candidates := viper.GetStringSlice("language.candidates")
+	if len(candidates) == 0 {
+		return defaultCandidates
+	}
+
+	return candidates
+}
+
+// Detect - the language of text out of the candidate ISO 639-1 codes, false when
+// it can't be detected confidently e.g. the text is too short
+func Detect(text string, candidates []string) (Language, bool) {
+	text = urlRegex.ReplaceAllString(text, " ")
+	text = tagRegex.ReplaceAllString(text, " ")
+
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+
+	if letters < minLetters {
+		return Language{}, false
+	}
+
+	whitelist := map[whatlanggo.Lang]bool{}
+	for _, code := range candidates {
+		if lang, ok := lookup(code); ok {
+			whitelist[lang] = true
+		}
+	}
+
+	info := whatlanggo.DetectWithOptions(text, whatlanggo.Options{Whitelist: whitelist})
+	if info.Confidence < minConfidence() || info.Lang.Iso6391() == "" {
+		return Language{}, false
+	}
+
+	return Language{Code: info.Lang.Iso6391(), Name: info.Lang.String()}, true
+}
+
+// ForTweet - the language to reply to a tweet in. A forced language always wins,
+// then the tweet's language out of the preferred languages, falling back to the
+// first preferred language. Without preferences any candidate language is
+// detected and false means the reply should simply follow the tweet.
+func ForTweet(tweetText string, preferred []string, forced string) (Language, bool) {
+	if l, ok := ByCode(forced); ok {
+		return l, true
+	}
+
+	if len(preferred) == 0 {
+		return Detect(tweetText, Candidates())
+	}
+
+	// scripts with a single language e.g. Han are detected regardless of the candidates
+	if l, ok := Detect(tweetText, preferred); ok && contains(preferred, l.Code) {
+		return l, true
+	}
+
+	for _, code := range preferred {
+		if l, ok := ByCode(code); ok {
+			return l, true
+		}
+	}
+
+	return Language{}, false
+}
+
+// ParseCodes - a comma or space separated list of ISO 639-1 codes, false when any
+// code is unknown
+func ParseCodes(list string) ([]string, bool) {
+	var codes []string
+	for _, code := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		l, ok := ByCode(code)
+		if !ok {
+			return nil, false
+		}
+		codes = append(codes, l.Code)
+	}
+
+	return codes, len(codes) > 0
+}
+
+// minConfidence - detections below language.minConfidence (0 to 1) are ignored
+func minConfidence() float64 {
+	if !viper.IsSet("language.minConfidence") {
+		return defaultMinConfidence
+	}
+
+	return viper.GetFloat64("language.minConfidence")
+}
+
+// contains
+func contains(codes []string, code string) bool {
+	for _, c := range codes {
+		if strings.EqualFold(strings.TrimSpace(c), code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookup
+func lookup(code string) (whatlanggo.Lang, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return 0, false
+	}
+
+	for lang := whatlanggo.Afr; lang <= whatlanggo.Zul; lang++ {
+		if lang.Iso6391() == code {
+			return lang, true
+		}
+	}
+
+	return 0, false
+}
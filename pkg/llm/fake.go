@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// FakeGenerator - deterministic reply generator for tests and local development
+type FakeGenerator struct {
+	replies []string
+	calls   int
+	mutex   sync.Mutex
+}
+
+// NewFakeGenerator - replies are returned in order and repeat once exhausted.
+// Without replies a reply is derived from a hash of the instruction.
+func NewFakeGenerator(replies ...string) *FakeGenerator {
+	return &FakeGenerator{
+		replies: replies,
+	}
+}
+
+// GenerateReply
+func (fg *FakeGenerator) GenerateReply(ctx context.Context, req Request) (Reply, error) {
+	fg.mutex.Lock()
+	defer fg.mutex.Unlock()
+
+	model := req.Model
+	if model == "" {
+		model = PROVIDER_FAKE
+	}
+
+	call := fg.calls
+	fg.calls++
+
+	var text string
+	if len(fg.replies) > 0 {
+		text = fg.replies[call%len(fg.replies)]
+	} else {
+		h := fnv.New32a()
+		h.Write([]byte(req.Instruction))
+		text = fmt.Sprintf("fake reply %08x", h.Sum32())
+	}
+
+	return Reply{
+		Text:          text,
+		Model:         model,
+		ResponseModel: model,
+		Usage: Usage{
+			PromptTokens:     len(strings.Fields(req.Instruction)),
+			CompletionTokens: len(strings.Fields(text)),
+		},
+	}, nil
+}
+
+// Calls - number of replies generated so far
+func (fg *FakeGenerator) Calls() int {
+	fg.mutex.Lock()
+	defer fg.mutex.Unlock()
+
+	return fg.calls
+}
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+var ErrNoChoices = errors.New("the model returned no choices")
+
+type openAIGenerator struct {
+	client *openai.Client
+}
+
+// NewOpenAIGenerator - reply generator using the OpenAI API
+func NewOpenAIGenerator(token string) ReplyGenerator {
+	return &openAIGenerator{
+		client: openai.NewClient(token),
+	}
+}
+
+// NewOpenAICompatibleGenerator - reply generator for any server exposing the
+// OpenAI chat completions API e.g. a self-hosted model behind vLLM or Ollama
+func NewOpenAICompatibleGenerator(token string, baseURL string) ReplyGenerator {
+	cfg := openai.DefaultConfig(token)
+	cfg.BaseURL = strings.TrimRight(baseURL, "/")
+
+	return &openAIGenerator{
+		client: openai.NewClientWithConfig(cfg),
+	}
+}
+
+// GenerateReply
+func (og *openAIGenerator) GenerateReply(ctx context.Context, req Request) (Reply, error) {
+	model := req.Model
+	if model == "" {
+		model = Model()
+	}
+
+	// the client omits zero values, the API's default presence penalty is 0 anyway
+	// but its default temperature is 1 so an explicit 0 is sent as the smallest float
+	var temperature, presencePenalty float32
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+		if temperature == 0 {
+			temperature = math.SmallestNonzeroFloat32
+		}
+	}
+
+	if req.PresencePenalty != nil {
+		presencePenalty = *req.PresencePenalty
+	}
+
+	resp, err := og.client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model:           model,
+			Temperature:     temperature,
+			MaxTokens:       req.MaxTokens,
+			PresencePenalty: presencePenalty,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: req.Instruction,
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return Reply{}, err
+	}
+
+	if len(resp.Choices) == 0 {
+		return Reply{}, ErrNoChoices
+	}
+
+	return Reply{
+		Text:          resp.Choices[0].Message.Content,
+		Model:         model,
+		ResponseModel: resp.Model,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+		},
+	}, nil
+}
@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Price - USD per 1K tokens, configured as a list under llm.prices since model
+// names contain dots which viper treats as key separators
+type Price struct {
+	Model      string  `mapstructure:"model"`
+	Prompt     float64 `mapstructure:"prompt"`
+	Completion float64 `mapstructure:"completion"`
+}
+
+// Prices
+func Prices() []Price {
+	var prices []Price
+	viper.UnmarshalKey("llm.prices", &prices)
+
+	return prices
+}
+
+// PriceForModel - exact match first, otherwise the longest configured model name
+// the model starts with so dated versions e.g. gpt-4-0613 match gpt-4. Price the
+// requested model, the provider's reported version can prefix match a different one
+func PriceForModel(model string) (Price, bool) {
+	var match Price
+	found := false
+
+	for _, p := range Prices() {
+		if p.Model == model {
+			return p, true
+		}
+
+		if strings.HasPrefix(model, p.Model) && len(p.Model) > len(match.Model) {
+			match = p
+			found = true
+		}
+	}
+
+	return match, found
+}
+
+// EstimateCost - estimated USD cost of usage, 0 when the model has no price
+func EstimateCost(model string, usage Usage) float64 {
+	p, ok := PriceForModel(model)
+	if !ok {
+		return 0
+	}
+
+	return float64(usage.PromptTokens)/1000*p.Prompt + float64(usage.CompletionTokens)/1000*p.Completion
+}
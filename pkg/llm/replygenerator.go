@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/spf13/viper"
+)
+
+const (
+	PROVIDER_OPENAI            = "openai"
+	PROVIDER_OPENAI_COMPATIBLE = "openaiCompatible"
+	PROVIDER_FAKE              = "fake"
+
+	defaultModel = openai.GPT4TurboPreview
+)
+
+// Request - a single reply generation request, nil parameters and a zero MaxTokens
+// use the provider's default
+type Request struct {
+	Model           string
+	Instruction     string
+	Temperature     *float32
+	MaxTokens       int
+	PresencePenalty *float32
+}
+
+// Reply - the generated reply. Model is the model requested and ResponseModel the
+// version the provider reports, e.g. gpt-4-0125-preview for gpt-4-turbo-preview
+type Reply struct {
+	Text          string
+	Model         string
+	ResponseModel string
+	Usage         Usage
+}
+
+// Usage - tokens spent generating a reply
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// ReplyGenerator - generates replies from an instruction using a language model
+type ReplyGenerator interface {
+	GenerateReply(ctx context.Context, req Request) (Reply, error)
+}
+
+// NewReplyGenerator - create the reply generator configured by llm.provider
+func NewReplyGenerator() (ReplyGenerator, error) {
+	provider := viper.GetString("llm.provider")
+
+	switch provider {
+	case "", PROVIDER_OPENAI:
+		return NewOpenAIGenerator(viper.GetString("openai.token")), nil
+	case PROVIDER_OPENAI_COMPATIBLE:
+		baseURL := viper.GetString("llm.baseUrl")
+		if baseURL == "" {
+			return nil, fmt.Errorf("llm.baseUrl must be set for provider %s", provider)
+		}
+
+		token := viper.GetString("llm.token")
+		if token == "" {
+			token = viper.GetString("openai.token")
+		}
+
+		return NewOpenAICompatibleGenerator(token, baseURL), nil
+	case PROVIDER_FAKE:
+		return NewFakeGenerator(viper.GetStringSlice("llm.fakeReplies")...), nil
+	}
+
+	return nil, fmt.Errorf("unknown llm provider %q", provider)
+}
+
+// Model - the model configured by llm.model, falling back to the default model
+func Model() string {
+	model := viper.GetString("llm.model")
+	if model == "" {
+		model = defaultModel
+	}
+
+	return model
+}
+
+// Models - the models chats may choose from, configured by llm.models and
+// limited to the default model when unset
+func Models() []string {
+	models := viper.GetStringSlice("llm.models")
+	if len(models) == 0 {
+		return []string{Model()}
+	}
+
+	return models
+}
+
+// ModelAllowed
+func ModelAllowed(model string) bool {
+	for _, m := range Models() {
+		if m == model {
+			return true
+		}
+	}
+
+	return false
+}
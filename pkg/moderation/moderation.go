@@ -0,0 +1,154 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	PROVIDER_OPENAI = "openai"
+	PROVIDER_NONE   = "none"
+
+	CHECK_BANNED_WORD = "bannedWord"
+	CHECK_BLOCKLIST   = "blocklist"
+	CHECK_PROVIDER    = "provider"
+)
+
+var ErrRejected = errors.New("the reply was rejected by content moderation")
+
+// Result - the outcome of moderating a reply, Check and Reason say why it was rejected
+type Result struct {
+	Allowed bool
+	Check   string
+	Reason  string
+}
+
+// Allowed - a result for a reply that passed
+func Allowed() Result {
+	return Result{Allowed: true}
+}
+
+// Rejected
+func Rejected(check string, reason string) Result {
+	return Result{
+		Check:  check,
+		Reason: reason,
+	}
+}
+
+// Provider - an external moderation service e.g. the OpenAI moderation endpoint
+type Provider interface {
+	Moderate(ctx context.Context, text string) (Result, error)
+}
+
+// NewProvider - create the provider configured by moderation.provider, none by default
+func NewProvider() (Provider, error) {
+	provider := viper.GetString("moderation.provider")
+
+	switch provider {
+	case "", PROVIDER_NONE:
+		return noopProvider{}, nil
+	case PROVIDER_OPENAI:
+		return NewOpenAIProvider(viper.GetString("openai.token")), nil
+	}
+
+	return nil, fmt.Errorf("unknown moderation provider %q", provider)
+}
+
+type noopProvider struct{}
+
+// Moderate
+func (noopProvider) Moderate(ctx context.Context, text string) (Result, error) {
+	return Allowed(), nil
+}
+
+// Moderator - checks replies against banned words, the regex blocklist and the provider
+type Moderator struct {
+	bannedWords []string
+	blocklist   []*regexp.Regexp
+	provider    Provider
+}
+
+// NewModerator - banned words and blocklist patterns from moderation.bannedWords and
+// moderation.blocklist apply to every chat
+func NewModerator() (*Moderator, error) {
+	provider, err := NewProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	var blocklist []*regexp.Regexp
+	for _, pattern := range viper.GetStringSlice("moderation.blocklist") {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid moderation.blocklist pattern %q: %w", pattern, err)
+		}
+		blocklist = append(blocklist, re)
+	}
+
+	return &Moderator{
+		bannedWords: viper.GetStringSlice("moderation.bannedWords"),
+		blocklist:   blocklist,
+		provider:    provider,
+	}, nil
+}
+
+// SetProvider - replace the provider e.g. with one that rejects everything in tests
+func (m *Moderator) SetProvider(provider Provider) {
+	m.provider = provider
+}
+
+// Check - bannedWords are the chat's own banned words, checked with the global
+// list. The provider is only called once the local checks pass.
+func (m *Moderator) Check(ctx context.Context, text string, bannedWords []string) (Result, error) {
+	words := append(append([]string{}, m.bannedWords...), bannedWords...)
+	if word, found := FindBannedWord(text, words); found {
+		return Rejected(CHECK_BANNED_WORD, fmt.Sprintf("contains banned word %q", word)), nil
+	}
+
+	for _, re := range m.blocklist {
+		if match := re.FindString(text); match != "" {
+			return Rejected(CHECK_BLOCKLIST, fmt.Sprintf("matches blocklist pattern %q with %q", re.String(), match)), nil
+		}
+	}
+
+	return m.provider.Moderate(ctx, text)
+}
+
+// FindBannedWord - the first banned word appearing in the text as a whole word or
+// phrase, ignoring case
+func FindBannedWord(text string, words []string) (string, bool) {
+	text = strings.ToLower(text)
+
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+
+		re := regexp.MustCompile(`(^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(word) + `($|[^\p{L}\p{N}_])`)
+		if re.MatchString(text) {
+			return word, true
+		}
+	}
+
+	return "", false
+}
+
+// ParseWords - a comma or newline separated list of words or phrases
+func ParseWords(list string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == '\n' }) {
+		word = strings.TrimSpace(word)
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
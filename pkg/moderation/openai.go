@@ -0,0 +1,68 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+type openAIProvider struct {
+	client *openai.Client
+}
+
+// NewOpenAIProvider - moderation using the OpenAI moderation endpoint
+func NewOpenAIProvider(token string) Provider {
+	return &openAIProvider{
+		client: openai.NewClient(token),
+	}
+}
+
+// Moderate
+func (op *openAIProvider) Moderate(ctx context.Context, text string) (Result, error) {
+	resp, err := op.client.Moderations(ctx, openai.ModerationRequest{
+		Input: text,
+		Model: openai.ModerationTextLatest,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, r := range resp.Results {
+		if r.Flagged {
+			return Rejected(CHECK_PROVIDER, fmt.Sprintf("flagged by openai moderation: %s", flaggedCategories(r.Categories))), nil
+		}
+	}
+
+	return Allowed(), nil
+}
+
+// flaggedCategories
+func flaggedCategories(c openai.ResultCategories) string {
+	categories := []struct {
+		name    string
+		flagged bool
+	}{
+		{"hate", c.Hate},
+		{"hate/threatening", c.HateThreatening},
+		{"self-harm", c.SelfHarm},
+		{"sexual", c.Sexual},
+		{"sexual/minors", c.SexualMinors},
+		{"violence", c.Violence},
+		{"violence/graphic", c.ViolenceGraphic},
+	}
+
+	var flagged []string
+	for _, category := range categories {
+		if category.flagged {
+			flagged = append(flagged, category.name)
+		}
+	}
+
+	if len(flagged) == 0 {
+		return "unspecified"
+	}
+
+	return strings.Join(flagged, ", ")
+}
@@ -0,0 +1,41 @@
+package moderation
+
+import (
+	"time"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rejection - a generated reply that failed moderation and why
+type Rejection struct {
+	RejectionRepository `json:"-" bson:"-"`
+	ID                  primitive.ObjectID `bson:"_id,omitempty"`
+	ChatID              int64              `bson:"chatId"`
+	ShillLinkID         primitive.ObjectID `bson:"shillLinkId,omitempty"`
+	Attempt             int                `bson:"attempt"`
+	Reply               string             `bson:"reply"`
+	Check               string             `bson:"check"`
+	Reason              string             `bson:"reason"`
+	Created             time.Time
+}
+
+// NewRejection
+func NewRejection(mongo *storage.Mongo) *Rejection {
+	return &Rejection{
+		RejectionRepository: NewRejectionRepository(mongo),
+	}
+}
+
+// RecordRejection - store the rejected reply with the check that failed it
+func RecordRejection(mongo *storage.Mongo, chatID int64, shillLinkID primitive.ObjectID, attempt int, reply string, result Result) error {
+	r := NewRejection(mongo)
+	r.ChatID = chatID
+	r.ShillLinkID = shillLinkID
+	r.Attempt = attempt
+	r.Reply = reply
+	r.Check = result.Check
+	r.Reason = result.Reason
+
+	return r.Insert(r)
+}
@@ -0,0 +1,47 @@
+package moderation
+
+import (
+	"context"
+	"time"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RejectionRepository interface {
+	Insert(r *Rejection) error
+	Collection() *mongo.Collection
+}
+
+// NewRejectionRepository
+func NewRejectionRepository(mongo *storage.Mongo) RejectionRepository {
+	return &rejectionRepository{mongo: mongo}
+}
+
+type rejectionRepository struct {
+	mongo *storage.Mongo
+}
+
+// Insert
+func (rr *rejectionRepository) Insert(r *Rejection) error {
+	r.Created = time.Now()
+
+	result, err := rr.Collection().InsertOne(
+		context.Background(),
+		r,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	r.ID = result.InsertedID.(primitive.ObjectID)
+
+	return err
+}
+
+// Collection
+func (rr *rejectionRepository) Collection() *mongo.Collection {
+	return rr.mongo.Collection("moderationRejection")
+}
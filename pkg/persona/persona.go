@@ -0,0 +1,124 @@
+package persona
+
+const (
+	PERSONA_SHILL       = "shill"
+	PERSONA_TROLL       = "troll"
+	PERSONA_HYPE        = "hype"
+	PERSONA_EDUCATIONAL = "educational"
+	PERSONA_MEME_LORD   = "memelord"
+	PERSONA_CALM_HOLDER = "calmholder"
+	PERSONA_FUD_BUSTER  = "fudbuster"
+)
+
+// Persona - a named voice the bot can reply in, the name is stored as the reply
+// type on shill links and the command is registered as a bot command
+type Persona struct {
+	Name        string
+	Command     string
+	Title       string
+	Description string
+	ButtonLabel string
+	Adjective   string
+	Action      string
+	Template    string
+}
+
+// registry - in the order personas are registered and displayed
+var registry = []Persona{
+	{
+		Name:        PERSONA_SHILL,
+		Command:     "shillx",
+		Title:       "Shill",
+		Description: "Create shill replies on X",
+		ButtonLabel: "SHILL NOW!!",
+		Adjective:   "shilling",
+		Action:      "SHILL",
+		Template:    shillTemplate,
+	},
+	{
+		Name:        PERSONA_TROLL,
+		Command:     "trollx",
+		Title:       "Troll",
+		Description: "Create troll replies on X",
+		ButtonLabel: "TROLL NOW!!",
+		Adjective:   "trolling",
+		Action:      "TROLL",
+		Template:    trollTemplate,
+	},
+	{
+		Name:        PERSONA_HYPE,
+		Command:     "hypex",
+		Title:       "Hype",
+		Description: "Create hype replies on X",
+		ButtonLabel: "HYPE NOW!!",
+		Adjective:   "hyping",
+		Action:      "HYPE",
+		Template:    hypeTemplate,
+	},
+	{
+		Name:        PERSONA_EDUCATIONAL,
+		Command:     "educatex",
+		Title:       "Educational",
+		Description: "Create informative replies on X",
+		ButtonLabel: "EDUCATE NOW!!",
+		Adjective:   "educating",
+		Action:      "EDUCATE",
+		Template:    educationalTemplate,
+	},
+	{
+		Name:        PERSONA_MEME_LORD,
+		Command:     "memex",
+		Title:       "Meme Lord",
+		Description: "Create meme replies on X",
+		ButtonLabel: "MEME NOW!!",
+		Adjective:   "meming",
+		Action:      "MEME",
+		Template:    memeLordTemplate,
+	},
+	{
+		Name:        PERSONA_CALM_HOLDER,
+		Command:     "calmx",
+		Title:       "Calm Holder",
+		Description: "Create calm, long-term holder replies on X",
+		ButtonLabel: "REPLY NOW!!",
+		Adjective:   "holding",
+		Action:      "REPLY",
+		Template:    calmHolderTemplate,
+	},
+	{
+		Name:        PERSONA_FUD_BUSTER,
+		Command:     "fudbusterx",
+		Title:       "FUD Buster",
+		Description: "Create replies that counter FUD on X",
+		ButtonLabel: "BUST FUD NOW!!",
+		Adjective:   "FUD busting",
+		Action:      "BUST FUD",
+		Template:    fudBusterTemplate,
+	},
+}
+
+// All - every registered persona
+func All() []Persona {
+	return registry
+}
+
+// ByName
+func ByName(name string) (Persona, bool) {
+	for _, p := range registry {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Persona{}, false
+}
+
+// Names - the names of every registered persona
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for _, p := range registry {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,29 @@
+package prompt
+
+import "gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/persona"
+
+// partials - named templates shared by every prompt
+const partials = `{{define "tags"}}When you create tweets you should try and include the hashtags "{{.Hashtags}}" and aim to keep the number of hashtags to a maxiumum of four but ideally keep to two.
+When you create tweets you should try and include the cashtags "{{.Cashtags}}" in the response.{{end}}
+{{- define "rules"}}{{with .BrandSafety.ForbiddenPhrases}}Never use the words or phrases {{range $i, $p := .}}{{if $i}}, {{end}}"{{$p}}"{{end}}.
+{{end}}{{with .BrandSafety.Competitors}}Never name or mention {{range $i, $c := .}}{{if $i}}, {{end}}{{$c}}{{end}}.
+{{end}}Never promise returns, predict prices or give financial advice.{{end}}
+{{- define "language"}}{{if .Language}}Write your response in {{.Language}}, keeping hashtags and cashtags as they are.{{else}}Write your response in the same language as the tweet.{{end}}{{end}}
+{{- define "respond"}}{{template "rules" .}}
+{{template "language" .}}
+Respond to the following tweet {{if .Style}}in a {{.Style}} style{{else}}in your unique style{{end}} and keep the response to a maximum of {{.CharLimit}} characters: '{{.TweetText}}'{{end}}`
+
+// DefaultTemplate - the built-in template for a reply type, each persona has its own
+func DefaultTemplate(replyType string) (string, bool) {
+	p, ok := persona.ByName(replyType)
+	if !ok {
+		return "", false
+	}
+
+	return p.Template, true
+}
+
+// ReplyTypes - reply types with a built-in template
+func ReplyTypes() []string {
+	return persona.Names()
+}
@@ -0,0 +1,99 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+)
+
+// Data - everything a prompt template can refer to, the chat's config fields
+// are promoted so templates can use {{.Token}}, {{.Hashtags}} etc. directly
+type Data struct {
+	config.Config
+	ReplyType string
+	TweetText string
+	CharLimit int
+	Style     string
+	// Language - name of the language to reply in, empty to follow the tweet
+	Language string
+}
+
+// Render - render the instruction for data.ReplyType, using the chat's override
+// when one is stored and the built-in default otherwise
+func Render(mongo *storage.Mongo, data Data) (string, error) {
+	text, err := TemplateText(mongo, data.ChatID, data.ReplyType)
+	if err != nil {
+		return "", err
+	}
+
+	return RenderTemplate(text, data)
+}
+
+// TemplateText - the template source used for a chat and reply type
+func TemplateText(mongo *storage.Mongo, chatID int64, replyType string) (string, error) {
+	pt, found, err := PromptTemplateByChatID(mongo, chatID, replyType)
+	if err != nil {
+		return "", err
+	}
+
+	if found {
+		return pt.Template, nil
+	}
+
+	text, ok := DefaultTemplate(replyType)
+	if !ok {
+		return "", fmt.Errorf("no prompt template for reply type %q", replyType)
+	}
+
+	return text, nil
+}
+
+// RenderTemplate - render template text, the shared partials e.g. {{template "respond" .}}
+// are available to every template
+func RenderTemplate(text string, data Data) (string, error) {
+	t, err := parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// Validate - check template text parses and renders against sample data
+func Validate(text string, replyType string) error {
+	_, err := RenderTemplate(text, SampleData(replyType))
+	return err
+}
+
+// SampleData - placeholder data for previewing and validating templates
+func SampleData(replyType string) Data {
+	return Data{
+		Config: config.Config{
+			Token:     "TOKEN",
+			Community: "the best community in crypto",
+			Hashtags:  "#TOKEN #crypto",
+			Cashtags:  "$TOKEN",
+		},
+		ReplyType: replyType,
+		TweetText: "gm, what are we buying today?",
+		CharLimit: 260,
+	}
+}
+
+// parse
+func parse(text string) (*template.Template, error) {
+	t, err := template.New("partials").Parse(partials)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.New("prompt").Parse(text)
+}
@@ -0,0 +1,52 @@
+package prompt
+
+import (
+	"time"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PromptTemplate - a chat's override of the built-in template for a reply type
+type PromptTemplate struct {
+	PromptTemplateRepository `json:"-" bson:"-"`
+	ID                       primitive.ObjectID `bson:"_id,omitempty"`
+	ChatID                   int64              `bson:"chatId"`
+	ReplyType                string             `bson:"replyType"`
+	Template                 string             `bson:"template"`
+	Created                  time.Time
+	Updated                  time.Time
+}
+
+// NewPromptTemplate
+func NewPromptTemplate(mongo *storage.Mongo) PromptTemplate {
+	return PromptTemplate{
+		PromptTemplateRepository: NewPromptTemplateRepository(mongo),
+	}
+}
+
+// PromptTemplateByChatID
+func PromptTemplateByChatID(mongo *storage.Mongo, chatID int64, replyType string) (PromptTemplate, bool, error) {
+	pt := NewPromptTemplate(mongo)
+
+	filter := bson.D{
+		{Key: "chatId", Value: chatID},
+		{Key: "replyType", Value: replyType},
+	}
+
+	results, err := pt.Find(filter, options.Find())
+	if err != nil {
+		return pt, false, err
+	}
+
+	if len(results) == 0 {
+		return pt, false, nil
+	}
+
+	pt = results[0]
+	pt.PromptTemplateRepository = NewPromptTemplateRepository(mongo)
+
+	return pt, true, nil
+}
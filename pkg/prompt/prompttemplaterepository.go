@@ -0,0 +1,92 @@
+package prompt
+
+import (
+	"context"
+	"time"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PromptTemplateRepository interface {
+	Insert(pt *PromptTemplate) error
+	Update(pt *PromptTemplate) error
+	Delete(pt *PromptTemplate) error
+	Find(filter bson.D, findOptions *options.FindOptions) ([]PromptTemplate, error)
+	Collection() *mongo.Collection
+}
+
+// NewPromptTemplateRepository
+func NewPromptTemplateRepository(mongo *storage.Mongo) PromptTemplateRepository {
+	return &promptTemplateRepository{mongo: mongo}
+}
+
+type promptTemplateRepository struct {
+	mongo *storage.Mongo
+}
+
+// Insert
+func (ptr *promptTemplateRepository) Insert(pt *PromptTemplate) error {
+	pt.Created = time.Now()
+	pt.Updated = time.Now()
+
+	result, err := ptr.Collection().InsertOne(
+		context.Background(),
+		pt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	pt.ID = result.InsertedID.(primitive.ObjectID)
+
+	return err
+}
+
+// Update
+func (ptr *promptTemplateRepository) Update(pt *PromptTemplate) error {
+	pt.Updated = time.Now()
+	filter := bson.M{"_id": bson.M{"$eq": pt.ID}}
+
+	_, err := ptr.Collection().ReplaceOne(
+		context.Background(),
+		filter,
+		pt,
+	)
+
+	return err
+}
+
+// Delete
+func (ptr *promptTemplateRepository) Delete(pt *PromptTemplate) error {
+	_, err := ptr.Collection().DeleteOne(
+		context.Background(),
+		bson.M{"_id": pt.ID},
+	)
+
+	return err
+}
+
+// Find
+func (ptr *promptTemplateRepository) Find(filter bson.D, findOptions *options.FindOptions) ([]PromptTemplate, error) {
+	var templates []PromptTemplate
+
+	ctx := context.Background()
+	cur, err := ptr.Collection().Find(ctx, filter, findOptions)
+	if err != nil {
+		return templates, err
+	}
+
+	err = cur.All(ctx, &templates)
+
+	return templates, err
+}
+
+// Collection
+func (ptr *promptTemplateRepository) Collection() *mongo.Collection {
+	return ptr.mongo.Collection("promptTemplate")
+}
@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+)
+
+var ErrQuotaExceeded = errors.New("generation quota reached")
+
+// Limits - generations allowed per chat, 0 or less is unlimited
+type Limits struct {
+	Daily   int
+	Monthly int
+}
+
+// DefaultLimits - configured by quota.daily and quota.monthly
+func DefaultLimits() Limits {
+	return Limits{
+		Daily:   viper.GetInt("quota.daily"),
+		Monthly: viper.GetInt("quota.monthly"),
+	}
+}
+
+// LimitsForChat - the chat's own limits where set, otherwise the defaults
+func LimitsForChat(c config.Config) Limits {
+	limits := DefaultLimits()
+
+	if c.Quota.Daily != 0 {
+		limits.Daily = c.Quota.Daily
+	}
+
+	if c.Quota.Monthly != 0 {
+		limits.Monthly = c.Quota.Monthly
+	}
+
+	return limits
+}
+
+type period struct {
+	key   string
+	limit int
+	ttl   time.Duration
+}
+
+// Quota - daily and monthly generation counts per chat
+type Quota struct {
+	counter storage.Counter
+}
+
+// NewQuota
+func NewQuota(counter storage.Counter) *Quota {
+	return &Quota{counter: counter}
+}
+
+// Consume - count one generation for the chat, returning ErrQuotaExceeded without
+// counting it when either limit has already been reached
+func (q *Quota) Consume(ctx context.Context, chatID int64, limits Limits) error {
+	now := time.Now().UTC()
+	periods := []period{
+		{
+			key:   fmt.Sprintf("quota:daily:%d:%s", chatID, now.Format("20060102")),
+			limit: limits.Daily,
+			ttl:   48 * time.Hour,
+		},
+		{
+			key:   fmt.Sprintf("quota:monthly:%d:%s", chatID, now.Format("200601")),
+			limit: limits.Monthly,
+			ttl:   32 * 24 * time.Hour,
+		},
+	}
+
+	var counted []string
+	for _, p := range periods {
+		if p.limit <= 0 {
+			continue
+		}
+
+		count, err := q.counter.Incr(ctx, p.key, p.ttl)
+		if err != nil {
+			q.rollback(ctx, counted)
+			return err
+		}
+		counted = append(counted, p.key)
+
+		if count > int64(p.limit) {
+			q.rollback(ctx, counted)
+			return ErrQuotaExceeded
+		}
+	}
+
+	return nil
+}
+
+// rollback
+func (q *Quota) rollback(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		q.counter.Decr(ctx, key)
+	}
+}
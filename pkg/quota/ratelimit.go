@@ -0,0 +1,51 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+)
+
+// RateLimit - requests allowed per window, disabled when either is 0
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// RateLimitFromConfig - configured by rateLimit.<name>.requests and rateLimit.<name>.window
+func RateLimitFromConfig(name string) RateLimit {
+	return RateLimit{
+		Requests: viper.GetInt(fmt.Sprintf("rateLimit.%s.requests", name)),
+		Window:   viper.GetDuration(fmt.Sprintf("rateLimit.%s.window", name)),
+	}
+}
+
+// RateLimiter - fixed window rate limits
+type RateLimiter struct {
+	counter storage.Counter
+}
+
+// NewRateLimiter
+func NewRateLimiter(counter storage.Counter) *RateLimiter {
+	return &RateLimiter{counter: counter}
+}
+
+// Allow - count a request for id, false once the limit for the current window is used up
+func (rl *RateLimiter) Allow(ctx context.Context, name string, id string, limit RateLimit) (bool, error) {
+	if limit.Requests <= 0 || limit.Window <= 0 {
+		return true, nil
+	}
+
+	window := time.Now().UnixNano() / int64(limit.Window)
+	key := fmt.Sprintf("ratelimit:%s:%s:%d", name, id, window)
+
+	count, err := rl.counter.Incr(ctx, key, limit.Window)
+	if err != nil {
+		return true, err
+	}
+
+	return count <= int64(limit.Requests), nil
+}
@@ -0,0 +1,123 @@
+package shillgptbot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/shillx"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/persona"
+	"go.uber.org/zap"
+)
+
+const tweetLinkCallbackPrefix = "tweetlink:"
+
+// detectTweetLink - in chats that opted in, offer to shill or troll any tweet link posted
+func (sb *ShillGPTBot) detectTweetLink(ctx context.Context, b *bot.Bot, update *models.Update) {
+	text := update.Message.Text
+	if strings.HasPrefix(text, "/") {
+		return
+	}
+
+	tweetURL, found := shillx.FindTweetURL(text)
+	if !found {
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+
+	c, found, err := config.ConfigByChatID(sb.mongo, chatID)
+	if err != nil {
+		sb.logger.Error(
+			"an error occurred trying to fetch config by chat ID",
+			zap.Int64("chatID", chatID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if !found || !c.AutoDetectLinks {
+		return
+	}
+
+	languageCode := ""
+	if update.Message.From != nil {
+		languageCode = update.Message.From.LanguageCode
+	}
+	tgh := sb.tgh.WithLocale(i18n.Resolve(c.Locale, languageCode))
+
+	var buttons []models.InlineKeyboardButton
+	for _, name := range []string{COMMAND_SHILL, COMMAND_TROLL} {
+		p, _ := persona.ByName(name)
+		if c.PersonaEnabled(name) {
+			buttons = append(buttons, models.InlineKeyboardButton{
+				Text:         tgh.T(i18n.AutoDetectButton, p.Title),
+				CallbackData: tweetLinkCallbackPrefix + name,
+			})
+		}
+	}
+
+	if len(buttons) == 0 {
+		return
+	}
+
+	kb := &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{buttons},
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:          chatID,
+		Text:            tgh.T(i18n.AutoDetectPrompt, tweetURL),
+		ReplyMarkup:     kb,
+		ReplyParameters: &models.ReplyParameters{MessageID: update.Message.ID},
+	})
+	if err != nil {
+		sb.logger.Error(
+			"an error occurred trying to send the tweet link keyboard",
+			zap.Int64("chatID", chatID),
+			zap.Error(err),
+		)
+	}
+}
+
+// tweetLinkCallbackHandler - start the chosen flow for whoever pressed the button,
+// the tweet link is taken from the keyboard message
+func (sb *ShillGPTBot) tweetLinkCallbackHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	query := update.CallbackQuery
+	defer b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+	})
+
+	if query.Message.Message == nil {
+		return
+	}
+
+	command := strings.TrimPrefix(query.Data, tweetLinkCallbackPrefix)
+	if _, ok := persona.ByName(command); !ok {
+		return
+	}
+
+	tweetURL, found := shillx.FindTweetURL(query.Message.Message.Text)
+	if !found {
+		return
+	}
+
+	sk := commandhandler.NewSessionKey(query.Message.Message.Chat.ID, query.From.ID)
+	defer sb.lockSession(sk)()
+
+	bs, ok := sb.startSessionCommand(ctx, b, sk, query.From, command)
+	if !ok {
+		return
+	}
+
+	tlh, ok := bs.commandHandler.(commandhandler.TweetLinkHandler)
+	if !ok {
+		return
+	}
+
+	tlh.StartWithTweetLink(ctx, b, sk, query.From, tweetURL)
+}
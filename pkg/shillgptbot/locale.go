@@ -0,0 +1,24 @@
+package shillgptbot
+
+import (
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/tghelper"
+	"go.uber.org/zap"
+)
+
+// localisedTGHelper - the helper in the chat's locale, falling back to the user's
+// Telegram language when the chat hasn't chosen one
+func (sb *ShillGPTBot) localisedTGHelper(chatID int64, user models.User) tghelper.TGHelper {
+	c, _, err := config.ConfigByChatID(sb.mongo, chatID)
+	if err != nil {
+		sb.logger.Warn(
+			"unable to fetch config for the chat's locale",
+			zap.Int64("chatID", chatID),
+			zap.Error(err),
+		)
+	}
+
+	return sb.tgh.WithLocale(i18n.Resolve(c.Locale, user.LanguageCode))
+}
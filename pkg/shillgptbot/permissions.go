@@ -0,0 +1,170 @@
+package shillgptbot
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/spf13/viper"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/persona"
+	"go.uber.org/zap"
+)
+
+const (
+	chatAdminKeyPrefix       = "chatAdmin:"
+	defaultChatAdminCacheTTL = 5 * time.Minute
+)
+
+// isChatAdmin - tghelper.IsChatAdmin cached in the state store for
+// permissions.adminCacheTtl so every command doesn't call getChatMember
+func (sb *ShillGPTBot) isChatAdmin(ctx context.Context, chatID int64, userID int64) (bool, error) {
+	key := fmt.Sprintf("%s%d:%d", chatAdminKeyPrefix, chatID, userID)
+
+	var isAdmin bool
+	ok, err := sb.store.Load(key, &isAdmin)
+	if err != nil {
+		sb.logger.Warn(
+			"unable to load cached chat admin",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+
+	if ok {
+		return isAdmin, nil
+	}
+
+	isAdmin, err = sb.tgh.IsChatAdmin(ctx, chatID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := sb.store.Save(key, isAdmin, chatAdminCacheTTL()); err != nil {
+		sb.logger.Warn(
+			"unable to cache chat admin",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+
+	return isAdmin, nil
+}
+
+// authorize - whether the user may run command under the chat's policy, chat
+// admins and bot managers always can
+func (sb *ShillGPTBot) authorize(ctx context.Context, chatID int64, userID int64, command string) (bool, error) {
+	c, _, err := config.ConfigByChatID(sb.mongo, chatID)
+	if err != nil {
+		return false, err
+	}
+
+	policy := c.CommandPolicy(commandName(command), defaultPolicy(command))
+	if policy.Policy == config.POLICY_EVERYONE || c.IsManager(userID) {
+		return true, nil
+	}
+
+	isAdmin, err := sb.isChatAdmin(ctx, chatID, userID)
+	if err != nil || isAdmin {
+		return isAdmin, err
+	}
+
+	return policy.Allows(c, userID), nil
+}
+
+// allowCommand - authorize, users the chat's policy doesn't allow to run the command
+// are told privately
+func (sb *ShillGPTBot) allowCommand(ctx context.Context, b *bot.Bot, chatID int64, user models.User, command string) bool {
+	allowed, err := sb.authorize(ctx, chatID, user.ID, command)
+	if err != nil {
+		sb.logger.Error(
+			"an error occurred trying to check the command policy",
+			zap.Int64("chatID", chatID),
+			zap.Int64("userID", user.ID),
+			zap.String("command", command),
+			zap.Error(err),
+		)
+		sb.tgh.SendErrorTryAgainMessage(ctx, b, chatID)
+		return false
+	}
+
+	if !allowed {
+		sb.sendNotAllowed(ctx, b, chatID, user, command)
+		return false
+	}
+
+	return true
+}
+
+// sendNotAllowed - tell the user privately so the group isn't spammed, users who
+// haven't started a private chat with the bot can't be messaged and are only logged
+func (sb *ShillGPTBot) sendNotAllowed(ctx context.Context, b *bot.Bot, chatID int64, user models.User, command string) {
+	tgh := sb.localisedTGHelper(chatID, user)
+
+	title := ""
+	if chat, err := b.GetChat(ctx, &bot.GetChatParams{ChatID: chatID}); err == nil {
+		title = chat.Title
+	}
+
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    user.ID,
+		Text:      tgh.T(i18n.NotAllowed, commandName(command), html.EscapeString(title)),
+		ParseMode: models.ParseModeHTML,
+	})
+	if err != nil {
+		sb.logger.Info(
+			"unable to send a private not allowed notice",
+			zap.Int64("chatID", chatID),
+			zap.Int64("userID", user.ID),
+			zap.String("command", command),
+			zap.Error(err),
+		)
+	}
+}
+
+// Commands - commands a chat can set a policy for, as typed without the slash
+func Commands() []string {
+	commands := []string{commandName(COMMAND_CONFIG), commandName(COMMAND_USAGE)}
+	for _, p := range persona.All() {
+		commands = append(commands, p.Command)
+	}
+
+	return commands
+}
+
+// commandName - the command as typed without the slash e.g. "shillx" for the shill persona
+func commandName(command string) string {
+	if p, ok := persona.ByName(command); ok {
+		return p.Command
+	}
+
+	return command
+}
+
+// defaultPolicy - the policy for chats that haven't set one, configured per command
+// by permissions.defaults.<command> e.g. permissions.defaults.shillx
+func defaultPolicy(command string) string {
+	if policy := viper.GetString("permissions.defaults." + commandName(command)); policy != "" {
+		return policy
+	}
+
+	if command == COMMAND_CONFIG || command == COMMAND_USAGE {
+		return config.POLICY_ADMINS
+	}
+
+	return config.POLICY_EVERYONE
+}
+
+// chatAdminCacheTTL
+func chatAdminCacheTTL() time.Duration {
+	ttl := viper.GetDuration("permissions.adminCacheTtl")
+	if ttl <= 0 {
+		ttl = defaultChatAdminCacheTTL
+	}
+
+	return ttl
+}
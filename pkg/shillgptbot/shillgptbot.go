@@ -6,7 +6,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"sync"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
@@ -15,6 +15,8 @@ import (
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/config"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/shillx"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler/trollx"
+	chatconfig "gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/config"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/persona"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
 	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/tghelper"
 	"go.uber.org/zap"
@@ -28,22 +30,27 @@ import (
 
 const (
 	COMMAND_NONE   = "none"
-	COMMAND_SHILL  = "shill"
-	COMMAND_TROLL  = "troll"
+	COMMAND_SHILL  = persona.PERSONA_SHILL
+	COMMAND_TROLL  = persona.PERSONA_TROLL
 	COMMAND_CONFIG = "config"
+	COMMAND_USAGE  = "usage"
+
+	botStateKeyPrefix     = "bot:"
+	expiryClaimKeyPrefix  = "expiry:"
+	defaultSessionTimeout = 10 * time.Minute
+	defaultSweepInterval  = 30 * time.Second
 )
 
 var (
 	telegramToken string
 	lastMessages  map[int64]*lastMessage
-	bState        map[int64]*botState
-
-	stateMutex = &sync.RWMutex{}
 )
 
 type botState struct {
-	activeCommand  string
-	user           models.User
+	SessionKey     commandhandler.SessionKey `json:"sessionKey"`
+	ActiveCommand  string                    `json:"activeCommand"`
+	User           models.User               `json:"user"`
+	LastActivity   time.Time                 `json:"lastActivity"`
 	commandHandler commandhandler.CommandHandler
 }
 
@@ -53,12 +60,15 @@ type lastMessage struct {
 }
 
 type ShillGPTBot struct {
-	bot    *bot.Bot
-	logger *zap.Logger
-	atom   *zap.AtomicLevel
-	mongo  *storage.Mongo
-	tgh    tghelper.TGHelper
-	ready  bool
+	bot             *bot.Bot
+	logger          *zap.Logger
+	atom            *zap.AtomicLevel
+	mongo           *storage.Mongo
+	store           storage.StateStore
+	sessionLocks    commandhandler.SessionLocks
+	commandHandlers map[string]commandhandler.CommandHandler
+	tgh             tghelper.TGHelper
+	ready           bool
 }
 
 // NewShillGPTBot
@@ -78,17 +88,38 @@ func NewShillGPTBot() *ShillGPTBot {
 	atom.SetLevel(zap.DebugLevel)
 
 	lastMessages = make(map[int64]*lastMessage)
-	bState = make(map[int64]*botState)
 
 	return &ShillGPTBot{
-		logger: logger,
-		atom:   &atom,
-		mongo:  storage.NewMongo(),
+		logger:          logger,
+		atom:            &atom,
+		mongo:           storage.NewMongo(),
+		store:           storage.SharedStateStore(),
+		commandHandlers: newCommandHandlers(),
 		// tclient: twitterOauth2Client(),
 		ready: false,
 	}
 }
 
+// newCommandHandlers - one handler per command, every persona gets its own
+func newCommandHandlers() map[string]commandhandler.CommandHandler {
+	commandHandlers := map[string]commandhandler.CommandHandler{
+		COMMAND_CONFIG: config.NewConfigCommandHandler(),
+	}
+
+	for _, p := range persona.All() {
+		switch p.Name {
+		case COMMAND_SHILL:
+			commandHandlers[p.Name] = shillx.NewShillCommandHandler()
+		case COMMAND_TROLL:
+			commandHandlers[p.Name] = trollx.NewTrollCommandHandler()
+		default:
+			commandHandlers[p.Name] = shillx.NewPersonaCommandHandler(p.Name)
+		}
+	}
+
+	return commandHandlers
+}
+
 // Run
 func (sb *ShillGPTBot) Run() {
 	telegramToken = viper.GetString("telegram.token")
@@ -113,85 +144,132 @@ func (sb *ShillGPTBot) Run() {
 
 	sb.registerHandlers()
 
+	go sb.expireSessions(ctx)
+
 	b.Start(ctx)
 }
 
 // registerHandlers
 func (sb *ShillGPTBot) registerHandlers() {
-	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/shillx", bot.MatchTypeExact, sb.shillHandler)
-	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/shillx@", bot.MatchTypePrefix, sb.shillHandler)
-	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/trollx", bot.MatchTypeExact, sb.trollHandler)
-	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/trollx@", bot.MatchTypePrefix, sb.trollHandler)
+	for _, p := range persona.All() {
+		command := "/" + p.Command
+		handler := sb.personaHandler(p.Name)
+
+		sb.bot.RegisterHandler(bot.HandlerTypeMessageText, command, bot.MatchTypeExact, handler)
+		sb.bot.RegisterHandler(bot.HandlerTypeMessageText, command+"@", bot.MatchTypePrefix, handler)
+		sb.bot.RegisterHandler(bot.HandlerTypeMessageText, command+" ", bot.MatchTypePrefix, handler)
+		sb.bot.RegisterHandler(bot.HandlerTypeMessageText, command+"\n", bot.MatchTypePrefix, handler)
+	}
+
 	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/cancel", bot.MatchTypeExact, sb.cancelHandler)
 	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/cancel@", bot.MatchTypePrefix, sb.cancelHandler)
 	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/start", bot.MatchTypeExact, sb.startHandler)
 	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/help", bot.MatchTypeExact, sb.helpHandler)
 	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/config", bot.MatchTypeExact, sb.configHandler)
 	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/config@", bot.MatchTypePrefix, sb.configHandler)
+	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/usage", bot.MatchTypeExact, sb.usageHandler)
+	sb.bot.RegisterHandler(bot.HandlerTypeMessageText, "/usage@", bot.MatchTypePrefix, sb.usageHandler)
+	sb.bot.RegisterHandler(bot.HandlerTypeCallbackQueryData, tweetLinkCallbackPrefix, bot.MatchTypePrefix, sb.tweetLinkCallbackHandler)
+	sb.bot.RegisterHandler(bot.HandlerTypeCallbackQueryData, tghelper.CancelCallbackPrefix, bot.MatchTypePrefix, sb.cancelCallbackHandler)
+	sb.bot.RegisterHandler(bot.HandlerTypeCallbackQueryData, commandhandler.ButtonCallbackPrefix, bot.MatchTypePrefix, sb.buttonCallbackHandler)
 }
 
-// shillHandler
-func (sb *ShillGPTBot) shillHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	stateMutex.Lock()
-	defer stateMutex.Unlock()
-
-	commandHandler := shillx.NewShillCommandHandler()
-	chatID := update.Message.Chat.ID
+// personaHandler - handler for a persona's command e.g. /shillx or /trollx
+func (sb *ShillGPTBot) personaHandler(name string) bot.HandlerFunc {
+	return func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		defer sb.lockSession(commandhandler.SessionKeyFromMessage(update.Message))()
 
-	bs, ok := sb.botState(chatID)
-	if !ok || bs.activeCommand != COMMAND_SHILL {
-		bs = &botState{
-			activeCommand:  COMMAND_SHILL,
-			user:           *update.Message.From,
-			commandHandler: commandHandler,
+		bs, ok := sb.startCommand(ctx, b, update, name)
+		if !ok {
+			return
 		}
-		bState[chatID] = bs
+
+		bs.commandHandler.Handle(ctx, b, update)
 	}
+}
 
-	bs.commandHandler.Handle(ctx, b, update)
+// cancelHandler
+func (sb *ShillGPTBot) cancelHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	defer sb.lockSession(commandhandler.SessionKeyFromMessage(update.Message))()
+
+	sb.cancel(ctx, b, update)
 }
 
-// trollHandler
-func (sb *ShillGPTBot) trollHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	stateMutex.Lock()
-	defer stateMutex.Unlock()
+// cancelCallbackHandler - the Cancel button on a session's prompts, only the
+// session's own user can press it
+func (sb *ShillGPTBot) cancelCallbackHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	query := update.CallbackQuery
+	defer b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+	})
+
+	chatID, userID, ok := tghelper.ParseCancelCallbackData(query.Data)
+	if !ok || query.From.ID != userID {
+		return
+	}
 
-	commandHandler := trollx.NewTrollCommandHandler()
-	chatID := update.Message.Chat.ID
+	sk := commandhandler.NewSessionKey(chatID, userID)
+	defer sb.lockSession(sk)()
 
-	bs, ok := sb.botState(chatID)
-	if !ok || bs.activeCommand != COMMAND_TROLL {
-		bs = &botState{
-			activeCommand:  COMMAND_TROLL,
-			user:           *update.Message.From,
-			commandHandler: commandHandler,
-		}
-		bState[chatID] = bs
+	// the prompt may belong to a session that has already ended
+	if query.Message.Message != nil {
+		sb.tgh.DeleteMessage(ctx, query.Message.Message.Chat.ID, query.Message.Message.ID)
 	}
 
-	bs.commandHandler.Handle(ctx, b, update)
+	sb.cancelSession(ctx, b, sk, query.From)
 }
 
-// cancelHandler
-func (sb *ShillGPTBot) cancelHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	stateMutex.Lock()
-	defer stateMutex.Unlock()
+// buttonCallbackHandler - a button on a session's prompt, see pressButton
+func (sb *ShillGPTBot) buttonCallbackHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	query := update.CallbackQuery
+	defer b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+	})
 
-	sb.cancel(ctx, b, update)
+	sb.pressButton(ctx, b, query)
+}
+
+// pressButton - pass the button to the command handler of the session it belongs
+// to, only the session's own user can press it. Pressing counts as activity for
+// the session timeout, the same as sending it a message.
+func (sb *ShillGPTBot) pressButton(ctx context.Context, b *bot.Bot, query *models.CallbackQuery) {
+	bc, ok := commandhandler.ParseButtonCallbackData(query.Data)
+	if !ok || query.From.ID != bc.SessionKey.UserID {
+		return
+	}
+
+	defer sb.lockSession(bc.SessionKey)()
+
+	bs, ok := sb.botState(bc.SessionKey)
+	if !ok || bs.ActiveCommand == COMMAND_NONE {
+		return
+	}
+
+	bh, ok := bs.commandHandler.(commandhandler.ButtonHandler)
+	if !ok {
+		return
+	}
+
+	sb.updateBotState(bs)
+	bh.Press(ctx, b, bc)
 }
 
 // cancel
 func (sb *ShillGPTBot) cancel(ctx context.Context, b *bot.Bot, update *models.Update) {
-	fmt.Printf("cancel!!")
-	chatID := update.Message.Chat.ID
+	sb.cancelSession(ctx, b, commandhandler.SessionKeyFromMessage(update.Message), *update.Message.From)
+}
 
-	bs, ok := sb.botState(chatID)
-	if ok {
-		bs.activeCommand = COMMAND_NONE
-		bs.commandHandler.Cancel(chatID)
+// cancelSession - the session's command handler removes its prompts
+func (sb *ShillGPTBot) cancelSession(ctx context.Context, b *bot.Bot, sk commandhandler.SessionKey, user models.User) {
+	bs, ok := sb.botState(sk)
+	if ok && bs.ActiveCommand != COMMAND_NONE {
+		bs.commandHandler.Cancel(ctx, b, sk)
+		bs.ActiveCommand = COMMAND_NONE
+		sb.updateBotState(bs)
 	}
 
-	sb.tgh.SendCancelledMessage(ctx, b, chatID)
+	tgh := sb.localisedTGHelper(sk.ChatID, user)
+	tgh.SendCancelledMessage(ctx, b, sk.ChatID)
 }
 
 // defaultHandler
@@ -200,13 +278,7 @@ func (sb *ShillGPTBot) defaultHandler(ctx context.Context, b *bot.Bot, update *m
 		return
 	}
 
-	message := bot.EscapeMarkdown(update.Message.Text)
-	if message == "cancelled" {
-		sb.cancel(ctx, b, update)
-		return
-	}
-
-	chatID := update.Message.Chat.ID
+	// chatID := update.Message.Chat.ID
 
 	// if _, ok := lastMessages[chatID]; !ok {
 	// 	lastMessages[chatID] = &lastMessage{}
@@ -215,23 +287,24 @@ func (sb *ShillGPTBot) defaultHandler(ctx context.Context, b *bot.Bot, update *m
 	// lastMessages[chatID].messageID = update.Message.ID
 	// lastMessages[chatID].text = update.Message.Text
 
-	bs, ok := sb.botState(chatID)
-	if !ok {
-		return
-	}
+	// sessions are per user so other members' messages never reach this session
+	sk := commandhandler.SessionKeyFromMessage(update.Message)
+	defer sb.lockSession(sk)()
 
-	if bs.activeCommand == COMMAND_NONE {
+	bs, ok := sb.botState(sk)
+	if !ok || bs.ActiveCommand == COMMAND_NONE {
+		sb.detectTweetLink(ctx, b, update)
 		return
 	}
 
-	if bs.user.ID != update.Message.From.ID {
+	if bs.commandHandler.Done(sk) {
+		bs.ActiveCommand = COMMAND_NONE
+		sb.updateBotState(bs)
+		sb.detectTweetLink(ctx, b, update)
 		return
 	}
 
-	if bs.commandHandler.Done(chatID) {
-		bs.activeCommand = COMMAND_NONE
-	}
-
+	sb.updateBotState(bs)
 	bs.commandHandler.Handle(ctx, b, update)
 }
 
@@ -247,32 +320,230 @@ func (sb *ShillGPTBot) helpHandler(ctx context.Context, b *bot.Bot, update *mode
 
 // configHandler
 func (sb *ShillGPTBot) configHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
-	stateMutex.Lock()
-	defer stateMutex.Unlock()
-
-	commandHandler := config.NewConfigCommandHandler()
-	chatID := update.Message.Chat.ID
-
-	bs, ok := sb.botState(chatID)
-	if !ok || bs.activeCommand != COMMAND_CONFIG {
-		bs = &botState{
-			activeCommand:  COMMAND_CONFIG,
-			user:           *update.Message.From,
-			commandHandler: commandHandler,
-		}
-		bState[chatID] = bs
+	defer sb.lockSession(commandhandler.SessionKeyFromMessage(update.Message))()
+
+	bs, ok := sb.startCommand(ctx, b, update, COMMAND_CONFIG)
+	if !ok {
+		return
 	}
 
-	sb.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
+	sb.tgh.DeleteMessage(ctx, update.Message.Chat.ID, update.Message.ID)
 	bs.commandHandler.Handle(ctx, b, update)
 }
 
-// botState
-func (sb *ShillGPTBot) botState(chatID int64) (*botState, bool) {
-	bs, ok := bState[chatID]
+// startCommand - switch the sender's session to command, resetting any other command
+// in progress. Commands the sender isn't allowed to run are deleted.
+func (sb *ShillGPTBot) startCommand(ctx context.Context, b *bot.Bot, update *models.Update, command string) (*botState, bool) {
+	if update.Message.From == nil {
+		return nil, false
+	}
+
+	bs, ok := sb.startSessionCommand(ctx, b, commandhandler.SessionKeyFromMessage(update.Message), *update.Message.From, command)
+	if !ok {
+		sb.tgh.DeleteMessage(ctx, update.Message.Chat.ID, update.Message.ID)
+	}
+
 	return bs, ok
 }
 
+// startSessionCommand - false when the chat's policy doesn't allow the user to run
+// the command, they're told privately and no session is started
+func (sb *ShillGPTBot) startSessionCommand(ctx context.Context, b *bot.Bot, sk commandhandler.SessionKey, user models.User, command string) (*botState, bool) {
+	if !sb.allowCommand(ctx, b, sk.ChatID, user, command) {
+		return nil, false
+	}
+
+	bs, ok := sb.botState(sk)
+	if !ok || bs.ActiveCommand != command {
+		if ok && bs.ActiveCommand != COMMAND_NONE {
+			bs.commandHandler.Reset(ctx, b, sk)
+		}
+		bs = sb.newBotState(sk, command, user)
+	}
+	sb.updateBotState(bs)
+
+	return bs, true
+}
+
+// newBotState
+func (sb *ShillGPTBot) newBotState(sk commandhandler.SessionKey, activeCommand string, user models.User) *botState {
+	return &botState{
+		SessionKey:     sk,
+		ActiveCommand:  activeCommand,
+		User:           user,
+		commandHandler: sb.commandHandlers[activeCommand],
+	}
+}
+
+// botState
+func (sb *ShillGPTBot) botState(sk commandhandler.SessionKey) (*botState, bool) {
+	return sb.loadBotState(botStateKey(sk))
+}
+
+// loadBotState - the command handler is restored from the active command
+func (sb *ShillGPTBot) loadBotState(key string) (*botState, bool) {
+	bs := &botState{}
+	ok, err := sb.store.Load(key, bs)
+	if err != nil {
+		sb.logger.Error(
+			"an error occurred trying to load bot state",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+		return nil, false
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	bs.commandHandler = sb.commandHandlers[bs.ActiveCommand]
+	if bs.commandHandler == nil {
+		bs.ActiveCommand = COMMAND_NONE
+	}
+
+	return bs, true
+}
+
+// updateBotState - saving the state counts as activity for the session timeout
+func (sb *ShillGPTBot) updateBotState(bs *botState) {
+	bs.LastActivity = time.Now()
+
+	if err := sb.store.Save(botStateKey(bs.SessionKey), bs, chatconfig.StateTTL(sb.mongo, bs.SessionKey.ChatID)); err != nil {
+		sb.logger.Error(
+			"an error occurred trying to save bot state",
+			zap.String("session", bs.SessionKey.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// lockSession - handlers lock the session they act on for as long as they use its
+// state, the returned func unlocks it
+func (sb *ShillGPTBot) lockSession(sk commandhandler.SessionKey) func() {
+	return sb.sessionLocks.Lock(sk)
+}
+
+// botStateKey
+func botStateKey(sk commandhandler.SessionKey) string {
+	return fmt.Sprintf("%s%s", botStateKeyPrefix, sk)
+}
+
+// expireSessions - periodically expire sessions that have been idle for longer than their timeout
+func (sb *ShillGPTBot) expireSessions(ctx context.Context) {
+	interval := viper.GetDuration("sessions.sweepInterval")
+	if interval <= 0 {
+		interval = defaultSweepInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			keys, err := sb.store.Keys(botStateKeyPrefix)
+			if err != nil {
+				sb.logger.Error(
+					"an error occurred trying to list sessions",
+					zap.Error(err),
+				)
+				continue
+			}
+
+			for _, key := range keys {
+				sb.expireSession(ctx, key)
+			}
+		}
+	}
+}
+
+// expireSession - finished sessions are closed quietly, abandoned ones have their
+// prompts removed and the chat is told the command timed out
+func (sb *ShillGPTBot) expireSession(ctx context.Context, key string) {
+	bs, ok := sb.loadBotState(key)
+	if !ok || bs.ActiveCommand == COMMAND_NONE {
+		return
+	}
+
+	// loaded again once locked, the session may have been used in the meantime
+	defer sb.lockSession(bs.SessionKey)()
+
+	bs, ok = sb.loadBotState(key)
+	if !ok || bs.ActiveCommand == COMMAND_NONE {
+		return
+	}
+
+	sk := bs.SessionKey
+
+	if bs.commandHandler.Done(sk) {
+		bs.ActiveCommand = COMMAND_NONE
+		sb.updateBotState(bs)
+		return
+	}
+
+	if time.Since(bs.LastActivity) < sessionTimeout(bs.ActiveCommand, bs.commandHandler) {
+		return
+	}
+
+	// every replica sweeps the same sessions, only the one claiming this idle
+	// period expires it
+	claimed, err := sb.store.Claim(expiryClaimKey(bs), chatconfig.StateTTL(sb.mongo, sk.ChatID))
+	if err != nil {
+		sb.logger.Error(
+			"an error occurred trying to claim session expiry",
+			zap.String("session", sk.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	sb.logger.Info(
+		"session timed out",
+		zap.String("session", sk.String()),
+		zap.String("command", bs.ActiveCommand),
+	)
+
+	bs.commandHandler.Reset(ctx, sb.bot, sk)
+	tgh := sb.localisedTGHelper(sk.ChatID, bs.User)
+	tgh.SendTimedOutMessage(ctx, sb.bot, sk.ChatID)
+
+	bs.ActiveCommand = COMMAND_NONE
+	sb.updateBotState(bs)
+}
+
+// expiryClaimKey - unique to the session's current idle period, so the session can
+// be expired again once it has been active
+func expiryClaimKey(bs *botState) string {
+	return fmt.Sprintf("%s%s:%d", expiryClaimKeyPrefix, bs.SessionKey, bs.LastActivity.UnixNano())
+}
+
+// sessionTimeout - idle time allowed for a command, configured per command by
+// sessions.timeout.<command> and falling back to the handler's own timeout, then
+// sessions.timeout.default
+func sessionTimeout(command string, handler commandhandler.CommandHandler) time.Duration {
+	timeout := viper.GetDuration("sessions.timeout." + command)
+	if th, ok := handler.(commandhandler.TimeoutHandler); ok && timeout <= 0 {
+		timeout = th.Timeout()
+	}
+
+	if timeout <= 0 {
+		timeout = viper.GetDuration("sessions.timeout.default")
+	}
+
+	if timeout <= 0 {
+		timeout = defaultSessionTimeout
+	}
+
+	return timeout
+}
+
 // Logger
 func (sb *ShillGPTBot) Logger() *zap.Logger {
 	atom := zap.NewAtomicLevel()
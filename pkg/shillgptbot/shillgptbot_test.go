@@ -0,0 +1,134 @@
+package shillgptbot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/commandhandler"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.uber.org/zap"
+)
+
+const testCommand = "test"
+
+// testCommandHandler - a session that never finishes on its own
+type testCommandHandler struct {
+	commandhandler.Command
+	timeout time.Duration
+	resets  int
+	presses []commandhandler.ButtonCallback
+}
+
+func (h *testCommandHandler) Press(ctx context.Context, b *bot.Bot, bc commandhandler.ButtonCallback) bool {
+	h.presses = append(h.presses, bc)
+	return true
+}
+
+func (h *testCommandHandler) Done(sk commandhandler.SessionKey) bool {
+	return false
+}
+
+func (h *testCommandHandler) Reset(ctx context.Context, b *bot.Bot, sk commandhandler.SessionKey) {
+	h.resets++
+}
+
+func (h *testCommandHandler) Timeout() time.Duration {
+	return h.timeout
+}
+
+func TestButtonPressKeepsSessionAlive(t *testing.T) {
+	handler := &testCommandHandler{timeout: time.Minute}
+	sb := &ShillGPTBot{
+		logger:          zap.NewNop(),
+		store:           storage.NewMemoryStateStore(),
+		commandHandlers: map[string]commandhandler.CommandHandler{testCommand: handler},
+	}
+
+	sk := commandhandler.NewSessionKey(-100, 42)
+	key := botStateKey(sk)
+
+	// the session was started longer ago than its timeout and only used with buttons since
+	idle := &botState{
+		SessionKey:    sk,
+		ActiveCommand: testCommand,
+		LastActivity:  time.Now().Add(-2 * handler.timeout),
+	}
+	if err := sb.store.Save(key, idle, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	sb.pressButton(context.Background(), nil, &models.CallbackQuery{
+		From: models.User{ID: sk.UserID},
+		Data: commandhandler.ButtonCallbackData(testCommand, "menu", 0, sk),
+	})
+
+	if len(handler.presses) != 1 {
+		t.Fatalf("the button was passed to the command handler %d times, want 1", len(handler.presses))
+	}
+
+	sb.expireSession(context.Background(), key)
+
+	if handler.resets != 0 {
+		t.Fatal("the session was expired after a button press")
+	}
+
+	bs, ok := sb.loadBotState(key)
+	if !ok || bs.ActiveCommand != testCommand {
+		t.Fatalf("the session's command is no longer active: %+v", bs)
+	}
+
+	if time.Since(bs.LastActivity) >= handler.timeout {
+		t.Errorf("the button press did not refresh the session's activity, last activity %s", bs.LastActivity)
+	}
+}
+
+func TestButtonPressFromAnotherUserIsIgnored(t *testing.T) {
+	handler := &testCommandHandler{timeout: time.Minute}
+	sb := &ShillGPTBot{
+		logger:          zap.NewNop(),
+		store:           storage.NewMemoryStateStore(),
+		commandHandlers: map[string]commandhandler.CommandHandler{testCommand: handler},
+	}
+
+	sk := commandhandler.NewSessionKey(-100, 42)
+	lastActivity := time.Now().Add(-time.Hour)
+	if err := sb.store.Save(botStateKey(sk), &botState{SessionKey: sk, ActiveCommand: testCommand, LastActivity: lastActivity}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	sb.pressButton(context.Background(), nil, &models.CallbackQuery{
+		From: models.User{ID: 7},
+		Data: commandhandler.ButtonCallbackData(testCommand, "menu", 0, sk),
+	})
+
+	if len(handler.presses) != 0 {
+		t.Error("another member's press was passed to the command handler")
+	}
+
+	bs, ok := sb.loadBotState(botStateKey(sk))
+	if !ok || !bs.LastActivity.Equal(lastActivity) {
+		t.Errorf("another member's press refreshed the session's activity: %+v", bs)
+	}
+}
+
+func TestButtonCallbackData(t *testing.T) {
+	sk := commandhandler.NewSessionKey(-1001234567890, 1234567890)
+	data := commandhandler.ButtonCallbackData("config", "setAIPresencePenalty", 12, sk)
+
+	if len(data) > 64 {
+		t.Errorf("callback data %q is longer than telegram allows", data)
+	}
+
+	bc, ok := commandhandler.ParseButtonCallbackData(data)
+	want := commandhandler.ButtonCallback{Conversation: "config", Step: "setAIPresencePenalty", Button: 12, SessionKey: sk}
+	if !ok || bc != want {
+		t.Errorf("ParseButtonCallbackData(%q) = %+v, %v, want %+v", data, bc, ok, want)
+	}
+
+	if _, ok := commandhandler.ParseButtonCallbackData(tweetLinkCallbackPrefix + "shill"); ok {
+		t.Error("a tweet link callback parsed as a conversation button")
+	}
+}
@@ -0,0 +1,72 @@
+package shillgptbot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/usage"
+	"go.uber.org/zap"
+)
+
+// usageHandler - token usage and estimated cost for the chat, admins only unless
+// the chat's usage policy allows others
+func (sb *ShillGPTBot) usageHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+	chatID := update.Message.Chat.ID
+
+	if update.Message.From == nil {
+		return
+	}
+
+	if !sb.allowCommand(ctx, b, chatID, *update.Message.From, COMMAND_USAGE) {
+		sb.tgh.DeleteMessage(ctx, chatID, update.Message.ID)
+		return
+	}
+
+	tgh := sb.localisedTGHelper(chatID, *update.Message.From)
+
+	now := time.Now().UTC()
+	periods := []struct {
+		title string
+		since time.Time
+	}{
+		{tgh.T(i18n.UsageToday), time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)},
+		{tgh.T(i18n.UsageThisMonth), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)},
+		{tgh.T(i18n.UsageAllTime), time.Time{}},
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "<b>%s</b>\n", tgh.T(i18n.UsageTitle))
+
+	for _, period := range periods {
+		totals, err := usage.TotalsByChatID(sb.mongo, chatID, period.since)
+		if err != nil {
+			sb.logger.Error(
+				"an error occurred trying to fetch usage totals",
+				zap.Int64("chatID", chatID),
+				zap.Error(err),
+			)
+			tgh.SendErrorTryAgainMessage(ctx, b, chatID)
+			return
+		}
+
+		sum := usage.Sum(totals)
+		fmt.Fprintf(
+			&message,
+			"\n%s\n",
+			tgh.T(i18n.UsagePeriod, period.title, sum.Requests, sum.PromptTokens, sum.CompletionTokens, sum.Cost),
+		)
+
+		if period.since.IsZero() {
+			for _, t := range totals {
+				fmt.Fprintf(&message, "  %s\n", tgh.T(i18n.UsageModel, t.Model, t.Requests, t.Cost))
+			}
+		}
+	}
+
+	tgh.SendMessage(ctx, b, chatID, message.String(), &models.ReplyParameters{})
+}
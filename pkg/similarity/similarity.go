@@ -0,0 +1,103 @@
+package similarity
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const DefaultShingleSize = 4
+
+var (
+	urlRegex = regexp.MustCompile(`(?i)https?://\S+`)
+	// hashtags, cashtags and mentions are shared by every reply in a raid so
+	// they are left out of the comparison
+	tagRegex = regexp.MustCompile(`[#$@][\p{L}\p{N}_]+`)
+)
+
+// Match - an earlier text and how similar it is
+type Match struct {
+	Text       string
+	Similarity float64
+}
+
+// Normalise - lower case words with urls, tags, punctuation and emoji removed
+func Normalise(text string) string {
+	text = urlRegex.ReplaceAllString(text, " ")
+	text = tagRegex.ReplaceAllString(text, " ")
+	text = strings.ToLower(text)
+
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return r
+		}
+		return ' '
+	}, text)
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Shingles - the set of character n-grams of the normalised text
+func Shingles(text string, n int) map[string]struct{} {
+	runes := []rune(Normalise(text))
+	shingles := make(map[string]struct{})
+
+	if len(runes) == 0 {
+		return shingles
+	}
+
+	if len(runes) <= n {
+		shingles[string(runes)] = struct{}{}
+		return shingles
+	}
+
+	for i := 0; i+n <= len(runes); i++ {
+		shingles[string(runes[i:i+n])] = struct{}{}
+	}
+
+	return shingles
+}
+
+// Jaccard - similarity of two texts from 0 (nothing in common) to 1 (identical
+// once normalised), comparing their character n-gram shingles
+func Jaccard(a string, b string) float64 {
+	return jaccard(Shingles(a, DefaultShingleSize), Shingles(b, DefaultShingleSize))
+}
+
+// jaccard
+func jaccard(a map[string]struct{}, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for shingle := range a {
+		if _, ok := b[shingle]; ok {
+			intersection++
+		}
+	}
+
+	union := len(a) + len(b) - intersection
+
+	return float64(intersection) / float64(union)
+}
+
+// Similar - earlier texts at least threshold similar to text, most similar first
+func Similar(text string, earlier []string, threshold float64) []Match {
+	shingles := Shingles(text, DefaultShingleSize)
+
+	var matches []Match
+	for _, e := range earlier {
+		similarity := jaccard(shingles, Shingles(e, DefaultShingleSize))
+		if similarity >= threshold {
+			matches = append(matches, Match{Text: e, Similarity: similarity})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+
+	return matches
+}
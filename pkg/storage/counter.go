@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+)
+
+const (
+	counterKeyPrefix = "shill-bot:counter:"
+
+	// memoryCounterPruneInterval - how often expired counts are removed from memory
+	memoryCounterPruneInterval = time.Minute
+)
+
+var (
+	sharedCounter     Counter
+	sharedCounterOnce sync.Once
+
+	// incrScript - INCR and expiry in one step, so a count can't be left without a
+	// ttl. Counts found without one are given the ttl too.
+	incrScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 or redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+)
+
+// Counter - expiring counters used for quotas and rate limits
+type Counter interface {
+	// Incr - increment key, starting a new count that expires after ttl if it doesn't exist
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Decr(ctx context.Context, key string) error
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// SharedCounter - redis backed when a redis host is configured so counts are shared
+// between replicas, otherwise in memory
+func SharedCounter() Counter {
+	sharedCounterOnce.Do(func() {
+		if viper.GetString("redis.host") != "" {
+			sharedCounter = NewRedisCounter(NewRedis())
+			return
+		}
+
+		sharedCounter = NewMemoryCounter()
+	})
+
+	return sharedCounter
+}
+
+type redisCounter struct {
+	redis *Redis
+}
+
+// NewRedisCounter
+func NewRedisCounter(redis *Redis) Counter {
+	return &redisCounter{redis: redis}
+}
+
+// Incr
+func (rc *redisCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, rc.redis, []string{counterKeyPrefix + key}, ttl.Milliseconds()).Int64()
+}
+
+// Decr
+func (rc *redisCounter) Decr(ctx context.Context, key string) error {
+	return rc.redis.Decr(ctx, counterKeyPrefix+key).Err()
+}
+
+// Get
+func (rc *redisCounter) Get(ctx context.Context, key string) (int64, error) {
+	count, err := rc.redis.Get(ctx, counterKeyPrefix+key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+
+	return count, err
+}
+
+type memoryCounterEntry struct {
+	count   int64
+	expires time.Time
+}
+
+type memoryCounter struct {
+	entries   map[string]memoryCounterEntry
+	lastPrune time.Time
+	mutex     sync.Mutex
+}
+
+// NewMemoryCounter - counter for a single instance, counts are lost on restart
+func NewMemoryCounter() Counter {
+	return &memoryCounter{
+		entries: make(map[string]memoryCounterEntry),
+	}
+}
+
+// Incr
+func (mc *memoryCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(mc.lastPrune) > memoryCounterPruneInterval {
+		mc.prune(now)
+	}
+
+	entry, ok := mc.entries[key]
+	if !ok || now.After(entry.expires) {
+		entry = memoryCounterEntry{expires: now.Add(ttl)}
+	}
+
+	entry.count++
+	mc.entries[key] = entry
+
+	return entry.count, nil
+}
+
+// prune - remove expired counts, the caller holds the mutex
+func (mc *memoryCounter) prune(now time.Time) {
+	for key, entry := range mc.entries {
+		if now.After(entry.expires) {
+			delete(mc.entries, key)
+		}
+	}
+
+	mc.lastPrune = now
+}
+
+// Decr
+func (mc *memoryCounter) Decr(ctx context.Context, key string) error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	if entry, ok := mc.entries[key]; ok {
+		entry.count--
+		mc.entries[key] = entry
+	}
+
+	return nil
+}
+
+// Get
+func (mc *memoryCounter) Get(ctx context.Context, key string) (int64, error) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	entry, ok := mc.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return 0, nil
+	}
+
+	return entry.count, nil
+}
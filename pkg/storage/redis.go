@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+)
+
+// Redis - redis client
+type Redis struct {
+	*redis.Client
+}
+
+// NewRedis - create a new redis instance
+func NewRedis() *Redis {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", viper.GetString("redis.host"), viper.GetString("redis.port")),
+		Password: viper.GetString("redis.password"),
+		DB:       viper.GetInt("redis.DB"),
+	})
+
+	return &Redis{client}
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+)
+
+const (
+	STATE_BACKEND_REDIS  = "redis"
+	STATE_BACKEND_MEMORY = "memory"
+
+	stateKeyPrefix  = "shill-bot:state:"
+	defaultStateTTL = 24 * time.Hour
+
+	// memoryStatePruneInterval - how often expired state is removed from memory
+	memoryStatePruneInterval = time.Minute
+)
+
+var (
+	sharedStateStore     StateStore
+	sharedStateStoreOnce sync.Once
+)
+
+// StateStore - persists conversational state as JSON, each key expiring after its ttl
+type StateStore interface {
+	Load(key string, v interface{}) (bool, error)
+	Save(key string, v interface{}, ttl time.Duration) error
+	Delete(key string) error
+	Keys(prefix string) ([]string, error)
+	// Claim - true for the first caller to claim key, every other caller gets false
+	// until ttl passes. Replicas sharing the store use it to act on a key only once.
+	Claim(key string, ttl time.Duration) (bool, error)
+}
+
+// SharedStateStore - the state store configured by state.backend, shared by the whole process.
+// Defaults to redis when a redis host is configured, otherwise memory.
+func SharedStateStore() StateStore {
+	sharedStateStoreOnce.Do(func() {
+		backend := viper.GetString("state.backend")
+		if backend == "" && viper.GetString("redis.host") != "" {
+			backend = STATE_BACKEND_REDIS
+		}
+
+		switch backend {
+		case STATE_BACKEND_REDIS:
+			sharedStateStore = NewRedisStateStore(NewRedis())
+		default:
+			sharedStateStore = NewMemoryStateStore()
+		}
+	})
+
+	return sharedStateStore
+}
+
+// DefaultStateTTL - how long state survives without being saved, configured by
+// state.ttl. Chats can set their own, see config.StateTTL
+func DefaultStateTTL() time.Duration {
+	ttl := viper.GetDuration("state.ttl")
+	if ttl <= 0 {
+		ttl = defaultStateTTL
+	}
+
+	return ttl
+}
+
+type redisStateStore struct {
+	redis *Redis
+}
+
+// NewRedisStateStore
+func NewRedisStateStore(redis *Redis) StateStore {
+	return &redisStateStore{redis: redis}
+}
+
+// Load
+func (rss *redisStateStore) Load(key string, v interface{}) (bool, error) {
+	data, err := rss.redis.Get(context.Background(), stateKeyPrefix+key).Bytes()
+	if err == redis.Nil {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, json.Unmarshal(data, v)
+}
+
+// Save
+func (rss *redisStateStore) Save(key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return rss.redis.Set(context.Background(), stateKeyPrefix+key, data, ttl).Err()
+}
+
+// Delete
+func (rss *redisStateStore) Delete(key string) error {
+	return rss.redis.Del(context.Background(), stateKeyPrefix+key).Err()
+}
+
+// Keys - keys starting with prefix
+func (rss *redisStateStore) Keys(prefix string) ([]string, error) {
+	ctx := context.Background()
+	keys := []string{}
+
+	var cursor uint64
+	for {
+		results, next, err := rss.redis.Scan(ctx, cursor, stateKeyPrefix+prefix+"*", 100).Result()
+		if err != nil {
+			return keys, err
+		}
+
+		for _, key := range results {
+			keys = append(keys, strings.TrimPrefix(key, stateKeyPrefix))
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return keys, nil
+}
+
+// Claim
+func (rss *redisStateStore) Claim(key string, ttl time.Duration) (bool, error) {
+	return rss.redis.SetNX(context.Background(), stateKeyPrefix+key, 1, ttl).Result()
+}
+
+type memoryStateEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+type memoryStateStore struct {
+	entries   map[string]memoryStateEntry
+	lastPrune time.Time
+	mutex     sync.RWMutex
+}
+
+// NewMemoryStateStore - state store for a single bot instance, state is lost on restart
+func NewMemoryStateStore() StateStore {
+	return &memoryStateStore{
+		entries: make(map[string]memoryStateEntry),
+	}
+}
+
+// Load
+func (mss *memoryStateStore) Load(key string, v interface{}) (bool, error) {
+	mss.mutex.RLock()
+	entry, ok := mss.entries[key]
+	mss.mutex.RUnlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		mss.Delete(key)
+		return false, nil
+	}
+
+	return true, json.Unmarshal(entry.data, v)
+}
+
+// Save
+func (mss *memoryStateStore) Save(key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	entry := memoryStateEntry{data: data}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+
+	mss.pruneIfDue(time.Now())
+	mss.entries[key] = entry
+
+	return nil
+}
+
+// Delete
+func (mss *memoryStateStore) Delete(key string) error {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+
+	delete(mss.entries, key)
+
+	return nil
+}
+
+// Keys - keys starting with prefix
+func (mss *memoryStateStore) Keys(prefix string) ([]string, error) {
+	mss.mutex.RLock()
+	defer mss.mutex.RUnlock()
+
+	keys := []string{}
+	for key, entry := range mss.entries {
+		if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+			continue
+		}
+
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Claim
+func (mss *memoryStateStore) Claim(key string, ttl time.Duration) (bool, error) {
+	mss.mutex.Lock()
+	defer mss.mutex.Unlock()
+
+	mss.pruneIfDue(time.Now())
+
+	if entry, ok := mss.entries[key]; ok && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+		return false, nil
+	}
+
+	entry := memoryStateEntry{data: []byte("1")}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	mss.entries[key] = entry
+
+	return true, nil
+}
+
+// pruneIfDue - remove expired state at most once per memoryStatePruneInterval, keys
+// that are never loaded again would otherwise stay in memory. The caller holds the
+// write lock
+func (mss *memoryStateStore) pruneIfDue(now time.Time) {
+	if now.Sub(mss.lastPrune) <= memoryStatePruneInterval {
+		return
+	}
+
+	for key, entry := range mss.entries {
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			delete(mss.entries, key)
+		}
+	}
+
+	mss.lastPrune = now
+}
@@ -0,0 +1,63 @@
+package tghelper
+
+import (
+	"github.com/go-telegram/bot/models"
+)
+
+// Keyboard - rows of inline buttons. The callback data says what a button does so
+// it can be handled by any replica, also after a restart.
+type Keyboard struct {
+	rows [][]models.InlineKeyboardButton
+}
+
+// NewKeyboard
+func NewKeyboard() *Keyboard {
+	return &Keyboard{}
+}
+
+// Row - start a new row, empty rows are left out
+func (kb *Keyboard) Row() *Keyboard {
+	kb.rows = append(kb.rows, []models.InlineKeyboardButton{})
+	return kb
+}
+
+// Button
+func (kb *Keyboard) Button(text string, callbackData string) *Keyboard {
+	kb.add(models.InlineKeyboardButton{
+		Text:         text,
+		CallbackData: callbackData,
+	})
+
+	return kb
+}
+
+// Cancel - a button ending the user's session through the bot's Cancel callback
+func (kb *Keyboard) Cancel(text string, chatID int64, userID int64) *Keyboard {
+	return kb.Button(text, CancelCallbackData(chatID, userID))
+}
+
+// Markup - nil when there are no buttons
+func (kb *Keyboard) Markup() models.ReplyMarkup {
+	var rows [][]models.InlineKeyboardButton
+	for _, row := range kb.rows {
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// add
+func (kb *Keyboard) add(button models.InlineKeyboardButton) {
+	if len(kb.rows) == 0 {
+		kb.Row()
+	}
+
+	last := len(kb.rows) - 1
+	kb.rows[last] = append(kb.rows[last], button)
+}
@@ -2,17 +2,23 @@ package tghelper
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
-	"github.com/go-telegram/ui/keyboard/inline"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/i18n"
 	"go.uber.org/zap"
 )
 
+// CancelCallbackPrefix - Cancel buttons carry the session they cancel e.g.
+// "cancel:<chatID>:<userID>", handled by the bot so they survive restarts
+const CancelCallbackPrefix = "cancel:"
+
 type TGHelper struct {
 	bot    *bot.Bot
 	logger *zap.Logger
+	locale string
 }
 
 // NewAction
@@ -20,9 +26,26 @@ func NewTGHelper(bot *bot.Bot, logger *zap.Logger) TGHelper {
 	return TGHelper{
 		bot:    bot,
 		logger: logger,
+		locale: i18n.DefaultLocale,
 	}
 }
 
+// WithLocale - a copy of the helper sending messages in locale
+func (tgh TGHelper) WithLocale(locale string) TGHelper {
+	tgh.locale = locale
+	return tgh
+}
+
+// Locale
+func (tgh *TGHelper) Locale() string {
+	return tgh.locale
+}
+
+// T - the message for key in the helper's locale
+func (tgh *TGHelper) T(key i18n.Key, args ...any) string {
+	return i18n.T(tgh.locale, key, args...)
+}
+
 // SendMessage
 func (tgh *TGHelper) SendMessage(ctx context.Context, b *bot.Bot, chatID int64, message string, replyParams *models.ReplyParameters) (*models.Message, error) {
 	params := &bot.SendMessageParams{
@@ -45,30 +68,19 @@ func (tgh *TGHelper) SendMessage(ctx context.Context, b *bot.Bot, chatID int64,
 	return sentMessage, err
 }
 
-// SendMessageWithCancel
-func (tgh *TGHelper) SendMessageWithCancel(ctx context.Context, b *bot.Bot, chatID int64, message string) (*models.Message, error) {
-	kb := inline.New(b).
-		Button("Cancel", []byte("cancel"), tgh.onKeyboardCancel)
-
-	return b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ReplyMarkup: kb,
-	})
+// CancelCallbackData
+func CancelCallbackData(chatID int64, userID int64) string {
+	return fmt.Sprintf("%s%d:%d", CancelCallbackPrefix, chatID, userID)
 }
 
-// SendMessageWithBackOrCancel
-func (tgh *TGHelper) SendMessageWithBackOrCancel(ctx context.Context, b *bot.Bot, chatID int64, message string) (*models.Message, error) {
-	kb := inline.New(b).
-		Row().
-		Button("Back", []byte("back"), tgh.onKeyboardBack).
-		Button("Cancel", []byte("cancel"), tgh.onKeyboardCancel)
+// ParseCancelCallbackData - the chat and user of the session a Cancel button belongs to
+func ParseCancelCallbackData(data string) (int64, int64, bool) {
+	var chatID, userID int64
+	if _, err := fmt.Sscanf(strings.TrimPrefix(data, CancelCallbackPrefix), "%d:%d", &chatID, &userID); err != nil {
+		return 0, 0, false
+	}
 
-	return b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID:      chatID,
-		Text:        message,
-		ReplyMarkup: kb,
-	})
+	return chatID, userID, true
 }
 
 // DeleteMessage
@@ -79,6 +91,24 @@ func (tgh *TGHelper) DeleteMessage(ctx context.Context, chatID int64, messageID
 	})
 }
 
+// IsChatAdmin - owners and administrators of a group, in a private chat the
+// chat ID is the user's own ID and they are always the admin
+func (tgh *TGHelper) IsChatAdmin(ctx context.Context, chatID int64, userID int64) (bool, error) {
+	if chatID == userID {
+		return true, nil
+	}
+
+	member, err := tgh.bot.GetChatMember(ctx, &bot.GetChatMemberParams{
+		ChatID: chatID,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return member.Type == models.ChatMemberTypeOwner || member.Type == models.ChatMemberTypeAdministrator, nil
+}
+
 // DeleteLastMessage
 func (tgh *TGHelper) DeleteLastMessage(ctx context.Context, chatID int64, messages []*models.Message) ([]*models.Message, error) {
 	numMessages := len(messages)
@@ -95,42 +125,42 @@ func (tgh *TGHelper) DeleteLastMessage(ctx context.Context, chatID int64, messag
 	return messages[:numMessages-1], nil
 }
 
-// DeleteAllMessages
+// DeleteAllMessages - messages that can't be deleted, e.g. already removed by
+// an inline keyboard, are dropped so persisted prompts don't go stale
 func (tgh *TGHelper) DeleteAllMessages(ctx context.Context, chatID int64, messages []*models.Message) ([]*models.Message, error) {
-	newMessages := messages
+	var lastErr error
 	for i := len(messages) - 1; i >= 0; i-- {
 		message := messages[i]
+		if message == nil {
+			continue
+		}
+
 		deleted, err := tgh.bot.DeleteMessage(ctx, &bot.DeleteMessageParams{
 			ChatID:    chatID,
 			MessageID: message.ID,
 		})
 
 		if !deleted {
-			return newMessages, err
+			lastErr = err
 		}
-
-		newMessages = newMessages[:len(newMessages)-1]
 	}
 
-	return newMessages, nil
-}
-
-// onKeyboardBackl
-func (tgh *TGHelper) onKeyboardBack(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	// to-do
-}
-
-// onKeyboardCancel
-func (tgh *TGHelper) onKeyboardCancel(ctx context.Context, b *bot.Bot, mes models.MaybeInaccessibleMessage, data []byte) {
-	chatID := mes.Message.Chat.ID
-	tgh.SendCancelledMessage(ctx, b, chatID)
+	return []*models.Message{}, lastErr
 }
 
 // SendCancelledMessage
 func (tgh *TGHelper) SendCancelledMessage(ctx context.Context, b *bot.Bot, chatID int64) {
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: chatID,
-		Text:   "cancelled",
+		Text:   tgh.T(i18n.Cancelled),
+	})
+}
+
+// SendTimedOutMessage
+func (tgh *TGHelper) SendTimedOutMessage(ctx context.Context, b *bot.Bot, chatID int64) {
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text:   tgh.T(i18n.TimedOut),
 	})
 }
 
@@ -138,7 +168,7 @@ func (tgh *TGHelper) SendCancelledMessage(ctx context.Context, b *bot.Bot, chatI
 func (tgh *TGHelper) SendErrorTryAgainMessage(ctx context.Context, b *bot.Bot, chatID int64) {
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: chatID,
-		Text:   "Oops, looks like we're having trouble, please try again.",
+		Text:   tgh.T(i18n.ErrorTryAgain),
 	})
 }
 
@@ -146,7 +176,7 @@ func (tgh *TGHelper) SendErrorTryAgainMessage(ctx context.Context, b *bot.Bot, c
 func (tgh *TGHelper) SendErrorNoConfig(ctx context.Context, b *bot.Bot, chatID int64) {
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: chatID,
-		Text:   "No config found, please run /config.",
+		Text:   tgh.T(i18n.ErrorNoConfig),
 	})
 }
 
@@ -154,7 +184,7 @@ func (tgh *TGHelper) SendErrorNoConfig(ctx context.Context, b *bot.Bot, chatID i
 func (tgh *TGHelper) SendErrorNoTokenName(ctx context.Context, b *bot.Bot, chatID int64) {
 	b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: chatID,
-		Text:   "Token name not set, please run /config and Set Token Name.",
+		Text:   tgh.T(i18n.ErrorNoTokenName),
 	})
 }
 
@@ -0,0 +1,95 @@
+package tweetfetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	paragraphRegex = regexp.MustCompile(`(?s)<p[^>]*>(.*?)</p>`)
+	lineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>`)
+	tagRegex       = regexp.MustCompile(`<[^>]+>`)
+)
+
+type oEmbedResponse struct {
+	URL        string `json:"url"`
+	AuthorName string `json:"author_name"`
+	HTML       string `json:"html"`
+}
+
+type oEmbedFetcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOEmbedFetcher - tweet fetcher using the publish.twitter.com oEmbed endpoint,
+// baseURL can point at a local stub serving the same /oembed response
+func NewOEmbedFetcher(baseURL string, timeout time.Duration) TweetFetcher {
+	return &oEmbedFetcher{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// FetchTweet
+func (of *oEmbedFetcher) FetchTweet(ctx context.Context, tweetURL string) (Tweet, error) {
+	query := url.Values{}
+	query.Set("url", tweetURL)
+	query.Set("omit_script", "true")
+	query.Set("dnt", "true")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, of.baseURL+"/oembed?"+query.Encode(), nil)
+	if err != nil {
+		return Tweet{}, err
+	}
+
+	resp, err := of.client.Do(req)
+	if err != nil {
+		return Tweet{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Tweet{}, ErrTweetNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Tweet{}, fmt.Errorf("oembed request failed with status %d", resp.StatusCode)
+	}
+
+	var oer oEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&oer); err != nil {
+		return Tweet{}, err
+	}
+
+	text := tweetTextFromHTML(oer.HTML)
+	if text == "" {
+		return Tweet{}, ErrNoTweetText
+	}
+
+	return Tweet{
+		URL:        oer.URL,
+		Text:       text,
+		AuthorName: oer.AuthorName,
+	}, nil
+}
+
+// tweetTextFromHTML - the tweet text is the first paragraph of the embed blockquote
+func tweetTextFromHTML(embed string) string {
+	matches := paragraphRegex.FindStringSubmatch(embed)
+	if matches == nil {
+		return ""
+	}
+
+	text := lineBreakRegex.ReplaceAllString(matches[1], "\n")
+	text = tagRegex.ReplaceAllString(text, "")
+
+	return strings.TrimSpace(html.UnescapeString(text))
+}
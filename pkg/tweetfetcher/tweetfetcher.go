@@ -0,0 +1,67 @@
+package tweetfetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	PROVIDER_OEMBED = "oembed"
+	PROVIDER_NONE   = "none"
+
+	defaultBaseURL = "https://publish.twitter.com"
+	defaultTimeout = 5 * time.Second
+)
+
+var (
+	ErrDisabled      = errors.New("tweet fetching is disabled")
+	ErrTweetNotFound = errors.New("tweet not found")
+	ErrNoTweetText   = errors.New("no tweet text in response")
+)
+
+// Tweet - the parts of a tweet needed to generate a reply
+type Tweet struct {
+	URL        string
+	Text       string
+	AuthorName string
+}
+
+// TweetFetcher - looks up a tweet from its status url
+type TweetFetcher interface {
+	FetchTweet(ctx context.Context, tweetURL string) (Tweet, error)
+}
+
+// NewTweetFetcher - create the tweet fetcher configured by tweetFetcher.provider
+func NewTweetFetcher() (TweetFetcher, error) {
+	provider := viper.GetString("tweetFetcher.provider")
+
+	switch provider {
+	case "", PROVIDER_OEMBED:
+		baseURL := viper.GetString("tweetFetcher.baseUrl")
+		if baseURL == "" {
+			baseURL = defaultBaseURL
+		}
+
+		timeout := viper.GetDuration("tweetFetcher.timeout")
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+
+		return NewOEmbedFetcher(baseURL, timeout), nil
+	case PROVIDER_NONE:
+		return noopFetcher{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown tweet fetcher provider %q", provider)
+}
+
+type noopFetcher struct{}
+
+// FetchTweet
+func (noopFetcher) FetchTweet(ctx context.Context, tweetURL string) (Tweet, error) {
+	return Tweet{}, ErrDisabled
+}
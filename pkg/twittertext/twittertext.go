@@ -0,0 +1,219 @@
+package twittertext
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxWeightedLength - maximum weighted length of a post
+	MaxWeightedLength = 280
+
+	// TransformedURLLength - every URL is shortened to a t.co link of this length
+	TransformedURLLength = 23
+
+	scale         = 100
+	defaultWeight = 200
+	emojiWeight   = 200
+)
+
+type weightRange struct {
+	start  rune
+	end    rune
+	weight int
+}
+
+// ranges not listed here (CJK, most symbols, etc.) use the default weight
+var weightRanges = []weightRange{
+	{0x0000, 0x10FF, 100},
+	{0x2000, 0x200D, 100},
+	{0x2010, 0x201F, 100},
+	{0x2032, 0x2037, 100},
+}
+
+var (
+	// any link with a scheme is a URL, bare domains only count with a known TLD
+	urlRegex = regexp.MustCompile(
+		`(?i)\bhttps?://[^\s/?#]+(?:[/?#][^\s]*)?` +
+			`|\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+` +
+			`(?:com|net|org|io|xyz|co|me|app|dev|gg|fi|finance|money|ai|tv|ly|info|us|uk|eu|de|fr|es|it|nl|ru|jp|cn|in|br)\b` +
+			`(?:[/?#][^\s]*)?`,
+	)
+
+	urlTrailingPunctuation = ".,!?;:'\")]}"
+)
+
+// WeightedLength - length of text as counted by X, following the weighted
+// counting rules of twitter-text v3
+func WeightedLength(text string) int {
+	weighted := 0
+	offset := 0
+
+	for _, loc := range urlSpans(text) {
+		weighted += textWeight(text[offset:loc[0]])
+		weighted += TransformedURLLength * scale
+		offset = loc[1]
+	}
+	weighted += textWeight(text[offset:])
+
+	return weighted / scale
+}
+
+// Valid - true when text fits within maxLength weighted characters
+func Valid(text string, maxLength int) bool {
+	return WeightedLength(text) <= maxLength
+}
+
+// Truncate - shorten text to at most maxLength weighted characters, cutting
+// at the last word boundary that fits
+func Truncate(text string, maxLength int) string {
+	text = strings.TrimSpace(text)
+	if Valid(text, maxLength) {
+		return text
+	}
+
+	truncated := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if truncated != "" {
+			candidate = truncated + " " + word
+		}
+
+		if !Valid(candidate, maxLength) {
+			break
+		}
+		truncated = candidate
+	}
+
+	// a single word longer than the limit, cut it rune by rune
+	if truncated == "" {
+		for _, r := range text {
+			candidate := truncated + string(r)
+			if !Valid(candidate, maxLength) {
+				break
+			}
+			truncated = candidate
+		}
+	}
+
+	return strings.TrimRight(truncated, " ,;:-–—")
+}
+
+// urlSpans - byte offsets of URLs in text, trailing punctuation excluded
+func urlSpans(text string) [][]int {
+	spans := urlRegex.FindAllStringIndex(text, -1)
+	for _, span := range spans {
+		for span[1] > span[0] {
+			r, size := utf8.DecodeLastRuneInString(text[span[0]:span[1]])
+			if !strings.ContainsRune(urlTrailingPunctuation, r) {
+				break
+			}
+			span[1] -= size
+		}
+	}
+
+	return spans
+}
+
+// textWeight - scaled weight of text without URLs
+func textWeight(text string) int {
+	runes := []rune(text)
+	weight := 0
+
+	for i := 0; i < len(runes); {
+		if n := emojiSequenceLength(runes[i:]); n > 0 {
+			weight += emojiWeight
+			i += n
+			continue
+		}
+
+		weight += runeWeight(runes[i])
+		i++
+	}
+
+	return weight
+}
+
+// runeWeight
+func runeWeight(r rune) int {
+	for _, wr := range weightRanges {
+		if r >= wr.start && r <= wr.end {
+			return wr.weight
+		}
+	}
+
+	return defaultWeight
+}
+
+// emojiSequenceLength - number of runes in the emoji sequence starting at
+// runes[0], or 0 when runes does not start with an emoji. A whole sequence,
+// including modifiers and zero width joiners, counts as a single emoji.
+func emojiSequenceLength(runes []rune) int {
+	if len(runes) == 0 {
+		return 0
+	}
+
+	first := runes[0]
+
+	if isRegionalIndicator(first) {
+		if len(runes) > 1 && isRegionalIndicator(runes[1]) {
+			return 2
+		}
+		return 1
+	}
+
+	// keycaps and text symbols such as © only become emoji with a selector
+	if !isEmoji(first) {
+		if len(runes) > 1 && (runes[1] == 0xFE0F || runes[1] == 0x20E3) && (unicode.IsDigit(first) || first == '#' || first == '*' || first > 0x7F) {
+			return 1 + emojiModifiersLength(runes[1:])
+		}
+		return 0
+	}
+
+	return 1 + emojiModifiersLength(runes[1:])
+}
+
+// emojiModifiersLength - number of runes continuing an emoji sequence
+func emojiModifiersLength(runes []rune) int {
+	n := 0
+	for n < len(runes) {
+		r := runes[n]
+		switch {
+		case r == 0xFE0F || r == 0x20E3:
+			n++
+		case r >= 0x1F3FB && r <= 0x1F3FF:
+			n++
+		case r >= 0xE0020 && r <= 0xE007F:
+			n++
+		case r == 0x200D && n+1 < len(runes) && (isEmoji(runes[n+1]) || runes[n+1] > 0x7F):
+			n += 2
+		default:
+			return n
+		}
+	}
+
+	return n
+}
+
+// isRegionalIndicator
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isEmoji
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	case r >= 0x2600 && r <= 0x27BF:
+		return true
+	case r >= 0x2300 && r <= 0x23FF:
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	}
+
+	return false
+}
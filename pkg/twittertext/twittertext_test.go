@@ -0,0 +1,66 @@
+package twittertext
+
+import "testing"
+
+func TestWeightedLength(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"empty", "", 0},
+		{"latin", "hello", 5},
+		{"cjk", "你好世界", 8},
+		{"mixed", "gm 你好", 7},
+		{"emoji", "😀", 2},
+		{"emoji with skin tone", "👍🏽", 2},
+		{"zwj sequence", "👨‍👩‍👧", 2},
+		{"flag", "🇺🇸", 2},
+		{"keycap", "1️⃣", 2},
+		{"url with scheme", "https://x.com/user/status/123", TransformedURLLength},
+		{"url with unlisted tld", "check https://pump.fun/coin/abc", 6 + TransformedURLLength},
+		{"bare domain", "example.com", TransformedURLLength},
+		{"bare domain with trailing punctuation", "see example.com.", 4 + TransformedURLLength + 1},
+		{"bare name with unlisted tld", "file.txt", 8},
+		{"url in parentheses", "(https://foo.bar)", 1 + TransformedURLLength + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WeightedLength(tt.text); got != tt.want {
+				t.Errorf("WeightedLength(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		maxLength int
+		want      string
+	}{
+		{"fits", "  hello world  ", 11, "hello world"},
+		{"word boundary", "hello world foo", 11, "hello world"},
+		{"partial word dropped", "hello world", 8, "hello"},
+		{"trailing punctuation trimmed", "one, two", 6, "one"},
+		{"single long word", "abcdefghij", 4, "abcd"},
+		{"cjk", "你好 世界", 5, "你好"},
+		{"emoji", "gm 😀😀 fren", 7, "gm 😀😀"},
+		{"url kept whole", "look https://pump.fun/coin/abc now", 28, "look https://pump.fun/coin/abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.text, tt.maxLength)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.maxLength, got, tt.want)
+			}
+
+			if !Valid(got, tt.maxLength) {
+				t.Errorf("Truncate(%q, %d) = %q is longer than the limit", tt.text, tt.maxLength, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package usage
+
+import (
+	"time"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/llm"
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Usage - tokens spent on a single generation attempt
+type Usage struct {
+	UsageRepository  `json:"-" bson:"-"`
+	ID               primitive.ObjectID `bson:"_id,omitempty"`
+	ChatID           int64              `bson:"chatId"`
+	ShillLinkID      primitive.ObjectID `bson:"shillLinkId,omitempty"`
+	Model            string             `bson:"model"`
+	ResponseModel    string             `bson:"responseModel,omitempty"`
+	Attempt          int                `bson:"attempt"`
+	PromptTokens     int                `bson:"promptTokens"`
+	CompletionTokens int                `bson:"completionTokens"`
+	Cost             float64            `bson:"cost"`
+	Created          time.Time
+}
+
+// Totals - usage summed per chat and model
+type Totals struct {
+	ChatID           int64   `bson:"chatId"`
+	Model            string  `bson:"model"`
+	Requests         int     `bson:"requests"`
+	PromptTokens     int     `bson:"promptTokens"`
+	CompletionTokens int     `bson:"completionTokens"`
+	Cost             float64 `bson:"cost"`
+}
+
+// NewUsage
+func NewUsage(mongo *storage.Mongo) *Usage {
+	return &Usage{
+		UsageRepository: NewUsageRepository(mongo),
+	}
+}
+
+// Record - store the usage of a generated reply with its estimated cost, priced
+// by the requested model
+func Record(mongo *storage.Mongo, chatID int64, shillLinkID primitive.ObjectID, attempt int, reply llm.Reply) error {
+	u := NewUsage(mongo)
+	u.ChatID = chatID
+	u.ShillLinkID = shillLinkID
+	u.Model = reply.Model
+	u.ResponseModel = reply.ResponseModel
+	u.Attempt = attempt
+	u.PromptTokens = reply.Usage.PromptTokens
+	u.CompletionTokens = reply.Usage.CompletionTokens
+	u.Cost = llm.EstimateCost(reply.Model, reply.Usage)
+
+	return u.Insert(u)
+}
+
+// TotalsByChatID - usage for a chat since the given time, per model
+func TotalsByChatID(mongo *storage.Mongo, chatID int64, since time.Time) ([]Totals, error) {
+	return NewUsageRepository(mongo).Totals(&chatID, since)
+}
+
+// AllTotals - usage for every chat since the given time, per chat and model
+func AllTotals(mongo *storage.Mongo, since time.Time) ([]Totals, error) {
+	return NewUsageRepository(mongo).Totals(nil, since)
+}
+
+// Sum - combine totals e.g. across models
+func Sum(totals []Totals) Totals {
+	var sum Totals
+	for _, t := range totals {
+		sum.Requests += t.Requests
+		sum.PromptTokens += t.PromptTokens
+		sum.CompletionTokens += t.CompletionTokens
+		sum.Cost += t.Cost
+	}
+
+	return sum
+}
@@ -0,0 +1,98 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"gitlab.totallydev.com/gritzb/shill-gpt-bot/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UsageRepository interface {
+	Insert(u *Usage) error
+	Totals(chatID *int64, since time.Time) ([]Totals, error)
+	Collection() *mongo.Collection
+}
+
+// NewUsageRepository
+func NewUsageRepository(mongo *storage.Mongo) UsageRepository {
+	return &usageRepository{mongo: mongo}
+}
+
+type usageRepository struct {
+	mongo *storage.Mongo
+}
+
+// Insert
+func (ur *usageRepository) Insert(u *Usage) error {
+	u.Created = time.Now()
+
+	result, err := ur.Collection().InsertOne(
+		context.Background(),
+		u,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	u.ID = result.InsertedID.(primitive.ObjectID)
+
+	return err
+}
+
+// Totals - usage grouped by chat and model, for a single chat when chatID is set
+func (ur *usageRepository) Totals(chatID *int64, since time.Time) ([]Totals, error) {
+	match := bson.D{
+		{Key: "created", Value: bson.D{{Key: "$gte", Value: since}}},
+	}
+	if chatID != nil {
+		match = append(match, bson.E{Key: "chatId", Value: *chatID})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "chatId", Value: "$chatId"},
+				{Key: "model", Value: "$model"},
+			}},
+			{Key: "requests", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "promptTokens", Value: bson.D{{Key: "$sum", Value: "$promptTokens"}}},
+			{Key: "completionTokens", Value: bson.D{{Key: "$sum", Value: "$completionTokens"}}},
+			{Key: "cost", Value: bson.D{{Key: "$sum", Value: "$cost"}}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "chatId", Value: "$_id.chatId"},
+			{Key: "model", Value: "$_id.model"},
+			{Key: "requests", Value: 1},
+			{Key: "promptTokens", Value: 1},
+			{Key: "completionTokens", Value: 1},
+			{Key: "cost", Value: 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "cost", Value: -1},
+			{Key: "chatId", Value: 1},
+		}}},
+	}
+
+	var totals []Totals
+
+	ctx := context.Background()
+	cur, err := ur.Collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return totals, err
+	}
+
+	err = cur.All(ctx, &totals)
+
+	return totals, err
+}
+
+// Collection
+func (ur *usageRepository) Collection() *mongo.Collection {
+	return ur.mongo.Collection("usage")
+}